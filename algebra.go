@@ -0,0 +1,559 @@
+package champ
+
+import "slices"
+
+// Intersect returns a map containing the entries of m1 whose keys are also present in m2.
+//
+// Intersect walks both tries together and reuses subtrees shared by m1 and m2.
+// If the maps use different hash functions, every entry of m1 is looked up in m2 instead.
+func Intersect[K comparable, V any](m1, m2 *Map[K, V]) *Map[K, V] {
+	return intersectMap(m1, m2)
+}
+
+// IntersectKeys returns a map containing the entries of m whose keys are present in keys.
+//
+// IntersectKeys walks the tries of m and keys together like Intersect.
+// If they use different hash functions, every entry of m is looked up in keys instead.
+func IntersectKeys[K comparable, V any](m *Map[K, V], keys *Set[K]) *Map[K, V] {
+	if m.root == nil || keys.root == nil {
+		return m.empty()
+	}
+
+	var root node[K, V]
+	var removed int
+	if m.hasher.same(keys.hasher) {
+		root, removed = intersectSetNode(m.root, keys.root, 0, m.hasher.hashFunc())
+	} else {
+		root, removed = filterNode(m.root, func(key K, _ V) bool {
+			return keys.Contains(key)
+		})
+	}
+	return m.withRoot(root, m.size-removed)
+}
+
+// Difference returns a map containing the entries of m1 whose keys are not present in m2.
+//
+// Difference walks both tries together and drops subtrees shared by m1 and m2.
+// If the maps use different hash functions, every entry of m1 is looked up in m2 instead.
+func Difference[K comparable, V any](m1, m2 *Map[K, V]) *Map[K, V] {
+	return differenceMap(m1, m2)
+}
+
+// DifferenceKeys returns a map containing the entries of m whose keys are not present in keys.
+//
+// DifferenceKeys walks the tries of m and keys together like Difference.
+// If they use different hash functions, every entry of m is looked up in keys instead.
+func DifferenceKeys[K comparable, V any](m *Map[K, V], keys *Set[K]) *Map[K, V] {
+	if m.root == nil || keys.root == nil {
+		return m
+	}
+
+	var root node[K, V]
+	var removed int
+	if m.hasher.same(keys.hasher) {
+		root, removed = differenceSetNode(m.root, keys.root, 0, m.hasher.hashFunc())
+	} else {
+		root, removed = filterNode(m.root, func(key K, _ V) bool {
+			return !keys.Contains(key)
+		})
+	}
+	return m.withRoot(root, m.size-removed)
+}
+
+// SymmetricDifference returns a map containing the entries whose keys are present in exactly one of m1 and m2.
+// The result uses the hash function of m1.
+func SymmetricDifference[K comparable, V any](m1, m2 *Map[K, V]) *Map[K, V] {
+	if m2.root == nil {
+		return m1
+	}
+
+	if !sameHasher(m1, m2) {
+		b := m1.Transient()
+		for k, v := range m2.All() {
+			if _, ok := m1.Get(k); ok {
+				b.Delete(k)
+			} else {
+				b.Set(k, v)
+			}
+		}
+		return b.Persistent()
+	}
+	if m1.root == nil {
+		return m2
+	}
+
+	root, common := symmetricDifferenceNode(m1.root, m2.root, 0, m1.hasher.hashFunc())
+	if root == nil {
+		return m1.empty()
+	}
+	return &Map[K, V]{
+		root:   root,
+		size:   m1.size + m2.size - 2*common,
+		hasher: m1.hasher,
+	}
+}
+
+func intersectMap[K comparable, V, W any](m1 *Map[K, V], m2 *Map[K, W]) *Map[K, V] {
+	if m1.root == nil || m2.root == nil {
+		return m1.empty()
+	}
+
+	var root node[K, V]
+	var removed int
+	if sameHasher(m1, m2) {
+		root, removed = intersectNode(m1.root, m2.root, 0, m1.hasher.hashFunc())
+	} else {
+		root, removed = filterNode(m1.root, func(key K, _ V) bool {
+			_, ok := m2.Get(key)
+			return ok
+		})
+	}
+	return m1.withRoot(root, m1.size-removed)
+}
+
+func differenceMap[K comparable, V, W any](m1 *Map[K, V], m2 *Map[K, W]) *Map[K, V] {
+	if m1.root == nil || m2.root == nil {
+		return m1
+	}
+
+	var root node[K, V]
+	var removed int
+	if sameHasher(m1, m2) {
+		root, removed = differenceNode(m1.root, m2.root, 0, m1.hasher.hashFunc())
+	} else {
+		root, removed = filterNode(m1.root, func(key K, _ V) bool {
+			_, ok := m2.Get(key)
+			return !ok
+		})
+	}
+	return m1.withRoot(root, m1.size-removed)
+}
+
+// intersectNode returns the subtree of n1 holding only the keys present in n2,
+// and the number of entries removed from n1.
+func intersectNode[K comparable, V, W any](
+	n1 node[K, V],
+	n2 node[K, W],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	// short-circuit for identical pointers
+	if any(n1) == any(n2) {
+		return n1, 0
+	}
+
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedNode[K, W]); ok {
+			return intersectBitmapIndexedNodes(n1, n2, shift, hashFunc)
+		}
+	}
+
+	return filterNode(n1, func(key K, _ V) bool {
+		_, ok := nodeGet(n2, key, hashFunc(key), shift)
+		return ok
+	})
+}
+
+func intersectBitmapIndexedNodes[K comparable, V, W any](
+	n1 *bitmapIndexedNode[K, V],
+	n2 *bitmapIndexedNode[K, W],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	result := &bitmapIndexedNode[K, V]{}
+	removed := 0
+
+	for bits := n1.datamap | n1.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		if n1.datamap&bit != 0 {
+			idx1 := popcount(n1.datamap & (bit - 1))
+			k1 := n1.keys[idx1]
+
+			var ok bool
+			switch {
+			case n2.datamap&bit != 0:
+				ok = n2.keys[popcount(n2.datamap&(bit-1))] == k1
+			case n2.nodemap&bit != 0:
+				_, ok = nodeGet(n2.nodes[popcount(n2.nodemap&(bit-1))], k1, hashFunc(k1), shift+bitsPerLevel)
+			}
+			if ok {
+				result.appendData(bit, k1, n1.values[idx1])
+			} else {
+				removed++
+			}
+			continue
+		}
+
+		child := n1.nodes[popcount(n1.nodemap&(bit-1))]
+		switch {
+		case n2.datamap&bit != 0:
+			k2 := n2.keys[popcount(n2.datamap&(bit-1))]
+			if v1, ok := nodeGet(child, k2, hashFunc(k2), shift+bitsPerLevel); ok {
+				result.appendData(bit, k2, v1)
+				removed += countNode(child) - 1
+			} else {
+				removed += countNode(child)
+			}
+		case n2.nodemap&bit != 0:
+			newChild, r := intersectNode(child, n2.nodes[popcount(n2.nodemap&(bit-1))], shift+bitsPerLevel, hashFunc)
+			result.appendChild(bit, newChild)
+			removed += r
+		default:
+			removed += countNode(child)
+		}
+	}
+
+	if removed == 0 {
+		return n1, 0
+	}
+	return result.orNil(), removed
+}
+
+// differenceNode returns the subtree of n1 holding only the keys not present in n2,
+// and the number of entries removed from n1.
+func differenceNode[K comparable, V, W any](
+	n1 node[K, V],
+	n2 node[K, W],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	// short-circuit for identical pointers
+	if any(n1) == any(n2) {
+		return nil, countNode(n1)
+	}
+
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedNode[K, W]); ok {
+			return differenceBitmapIndexedNodes(n1, n2, shift, hashFunc)
+		}
+	}
+
+	return filterNode(n1, func(key K, _ V) bool {
+		_, ok := nodeGet(n2, key, hashFunc(key), shift)
+		return !ok
+	})
+}
+
+func differenceBitmapIndexedNodes[K comparable, V, W any](
+	n1 *bitmapIndexedNode[K, V],
+	n2 *bitmapIndexedNode[K, W],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	result := &bitmapIndexedNode[K, V]{}
+	removed := 0
+
+	for bits := n1.datamap | n1.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		if n1.datamap&bit != 0 {
+			idx1 := popcount(n1.datamap & (bit - 1))
+			k1 := n1.keys[idx1]
+
+			var found bool
+			switch {
+			case n2.datamap&bit != 0:
+				found = n2.keys[popcount(n2.datamap&(bit-1))] == k1
+			case n2.nodemap&bit != 0:
+				_, found = nodeGet(n2.nodes[popcount(n2.nodemap&(bit-1))], k1, hashFunc(k1), shift+bitsPerLevel)
+			}
+			if found {
+				removed++
+			} else {
+				result.appendData(bit, k1, n1.values[idx1])
+			}
+			continue
+		}
+
+		child := n1.nodes[popcount(n1.nodemap&(bit-1))]
+		switch {
+		case n2.datamap&bit != 0:
+			k2 := n2.keys[popcount(n2.datamap&(bit-1))]
+			newChild, deleted := nodeDel(child, k2, hashFunc(k2), shift+bitsPerLevel)
+			result.appendChild(bit, newChild)
+			if deleted {
+				removed++
+			}
+		case n2.nodemap&bit != 0:
+			newChild, r := differenceNode(child, n2.nodes[popcount(n2.nodemap&(bit-1))], shift+bitsPerLevel, hashFunc)
+			result.appendChild(bit, newChild)
+			removed += r
+		default:
+			result.appendNode(bit, child)
+		}
+	}
+
+	if removed == 0 {
+		return n1, 0
+	}
+	return result.orNil(), removed
+}
+
+// intersectSetNode returns the subtree of n1 holding only the keys present in the set trie n2,
+// and the number of entries removed from n1.
+func intersectSetNode[K comparable, V any](
+	n1 node[K, V],
+	n2 setNode[K],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedSetNode[K]); ok {
+			return intersectBitmapIndexedSetNodes(n1, n2, shift, hashFunc)
+		}
+	}
+
+	return filterNode(n1, func(key K, _ V) bool {
+		return n2.contains(key, hashFunc(key), shift)
+	})
+}
+
+func intersectBitmapIndexedSetNodes[K comparable, V any](
+	n1 *bitmapIndexedNode[K, V],
+	n2 *bitmapIndexedSetNode[K],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	result := &bitmapIndexedNode[K, V]{}
+	removed := 0
+
+	for bits := n1.datamap | n1.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		if n1.datamap&bit != 0 {
+			idx1 := popcount(n1.datamap & (bit - 1))
+			k1 := n1.keys[idx1]
+
+			var ok bool
+			switch {
+			case n2.datamap&bit != 0:
+				ok = n2.keys[popcount(n2.datamap&(bit-1))] == k1
+			case n2.nodemap&bit != 0:
+				ok = n2.nodes[popcount(n2.nodemap&(bit-1))].contains(k1, hashFunc(k1), shift+bitsPerLevel)
+			}
+			if ok {
+				result.appendData(bit, k1, n1.values[idx1])
+			} else {
+				removed++
+			}
+			continue
+		}
+
+		child := n1.nodes[popcount(n1.nodemap&(bit-1))]
+		switch {
+		case n2.datamap&bit != 0:
+			k2 := n2.keys[popcount(n2.datamap&(bit-1))]
+			if v1, ok := nodeGet(child, k2, hashFunc(k2), shift+bitsPerLevel); ok {
+				result.appendData(bit, k2, v1)
+				removed += countNode(child) - 1
+			} else {
+				removed += countNode(child)
+			}
+		case n2.nodemap&bit != 0:
+			newChild, r := intersectSetNode(child, n2.nodes[popcount(n2.nodemap&(bit-1))], shift+bitsPerLevel, hashFunc)
+			result.appendChild(bit, newChild)
+			removed += r
+		default:
+			removed += countNode(child)
+		}
+	}
+
+	if removed == 0 {
+		return n1, 0
+	}
+	return result.orNil(), removed
+}
+
+// differenceSetNode returns the subtree of n1 holding only the keys not present in the set trie n2,
+// and the number of entries removed from n1.
+func differenceSetNode[K comparable, V any](
+	n1 node[K, V],
+	n2 setNode[K],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedSetNode[K]); ok {
+			return differenceBitmapIndexedSetNodes(n1, n2, shift, hashFunc)
+		}
+	}
+
+	return filterNode(n1, func(key K, _ V) bool {
+		return !n2.contains(key, hashFunc(key), shift)
+	})
+}
+
+func differenceBitmapIndexedSetNodes[K comparable, V any](
+	n1 *bitmapIndexedNode[K, V],
+	n2 *bitmapIndexedSetNode[K],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	result := &bitmapIndexedNode[K, V]{}
+	removed := 0
+
+	for bits := n1.datamap | n1.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		if n1.datamap&bit != 0 {
+			idx1 := popcount(n1.datamap & (bit - 1))
+			k1 := n1.keys[idx1]
+
+			var found bool
+			switch {
+			case n2.datamap&bit != 0:
+				found = n2.keys[popcount(n2.datamap&(bit-1))] == k1
+			case n2.nodemap&bit != 0:
+				found = n2.nodes[popcount(n2.nodemap&(bit-1))].contains(k1, hashFunc(k1), shift+bitsPerLevel)
+			}
+			if found {
+				removed++
+			} else {
+				result.appendData(bit, k1, n1.values[idx1])
+			}
+			continue
+		}
+
+		child := n1.nodes[popcount(n1.nodemap&(bit-1))]
+		switch {
+		case n2.datamap&bit != 0:
+			k2 := n2.keys[popcount(n2.datamap&(bit-1))]
+			newChild, deleted := nodeDel(child, k2, hashFunc(k2), shift+bitsPerLevel)
+			result.appendChild(bit, newChild)
+			if deleted {
+				removed++
+			}
+		case n2.nodemap&bit != 0:
+			newChild, r := differenceSetNode(child, n2.nodes[popcount(n2.nodemap&(bit-1))], shift+bitsPerLevel, hashFunc)
+			result.appendChild(bit, newChild)
+			removed += r
+		default:
+			result.appendNode(bit, child)
+		}
+	}
+
+	if removed == 0 {
+		return n1, 0
+	}
+	return result.orNil(), removed
+}
+
+// symmetricDifferenceNode returns the subtree holding the keys present in exactly one of n1 and n2,
+// and the number of keys present in both.
+func symmetricDifferenceNode[K comparable, V any](
+	n1, n2 node[K, V],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	// short-circuit for identical pointers
+	if n1 == n2 {
+		return nil, countNode(n1)
+	}
+
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedNode[K, V]); ok {
+			return symmetricDifferenceBitmapIndexedNodes(n1, n2, shift, hashFunc)
+		}
+	}
+
+	// Nodes of different kinds never meet in a canonical trie, so both are collision nodes at the maximum depth.
+	return symmetricDifferenceCollisionNodes(n1.(*collisionNode[K, V]), n2.(*collisionNode[K, V]))
+}
+
+// symmetricDifferenceCollisionNodes returns the entries of n1 whose keys are absent from n2 followed by those of n2 absent from n1,
+// and the number of keys present in both.
+func symmetricDifferenceCollisionNodes[K comparable, V any](n1, n2 *collisionNode[K, V]) (node[K, V], int) {
+	keys := make([]K, 0, len(n1.keys)+len(n2.keys))
+	values := make([]V, 0, len(n1.values)+len(n2.values))
+	for i, k1 := range n1.keys {
+		if !slices.Contains(n2.keys, k1) {
+			keys = append(keys, k1)
+			values = append(values, n1.values[i])
+		}
+	}
+	common := len(n1.keys) - len(keys)
+	for i, k2 := range n2.keys {
+		if !slices.Contains(n1.keys, k2) {
+			keys = append(keys, k2)
+			values = append(values, n2.values[i])
+		}
+	}
+
+	return newCollisionNode(keys, values), common
+}
+
+func symmetricDifferenceBitmapIndexedNodes[K comparable, V any](
+	n1, n2 *bitmapIndexedNode[K, V],
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	result := &bitmapIndexedNode[K, V]{}
+	common := 0
+
+	// toggle inserts the entry into the sub-node if its key is absent, and removes it otherwise.
+	toggle := func(bit uint32, child node[K, V], key K, value V) {
+		h := hashFunc(key)
+		if newChild, deleted := nodeDel(child, key, h, shift+bitsPerLevel); deleted {
+			result.appendChild(bit, newChild)
+			common++
+			return
+		}
+		newChild, _ := nodeSet(child, key, value, h, shift+bitsPerLevel, hashFunc)
+		result.appendNode(bit, newChild)
+	}
+
+	for bits := n1.datamap | n1.nodemap | n2.datamap | n2.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		switch {
+		case n1.datamap&bit != 0:
+			idx1 := popcount(n1.datamap & (bit - 1))
+			k1, v1 := n1.keys[idx1], n1.values[idx1]
+
+			switch {
+			case n2.datamap&bit != 0:
+				idx2 := popcount(n2.datamap & (bit - 1))
+				k2, v2 := n2.keys[idx2], n2.values[idx2]
+				if k1 == k2 {
+					common++
+				} else {
+					result.appendNode(bit, result.createSubNode(
+						nil,
+						k1, v1, hashFunc(k1),
+						k2, v2, hashFunc(k2),
+						shift+bitsPerLevel,
+					))
+				}
+			case n2.nodemap&bit != 0:
+				toggle(bit, n2.nodes[popcount(n2.nodemap&(bit-1))], k1, v1)
+			default:
+				result.appendData(bit, k1, v1)
+			}
+
+		case n1.nodemap&bit != 0:
+			child := n1.nodes[popcount(n1.nodemap&(bit-1))]
+
+			switch {
+			case n2.datamap&bit != 0:
+				idx2 := popcount(n2.datamap & (bit - 1))
+				toggle(bit, child, n2.keys[idx2], n2.values[idx2])
+			case n2.nodemap&bit != 0:
+				newChild, c := symmetricDifferenceNode(child, n2.nodes[popcount(n2.nodemap&(bit-1))], shift+bitsPerLevel, hashFunc)
+				result.appendChild(bit, newChild)
+				common += c
+			default:
+				result.appendNode(bit, child)
+			}
+
+		case n2.datamap&bit != 0:
+			idx2 := popcount(n2.datamap & (bit - 1))
+			result.appendData(bit, n2.keys[idx2], n2.values[idx2])
+
+		default:
+			result.appendNode(bit, n2.nodes[popcount(n2.nodemap&(bit-1))])
+		}
+	}
+
+	return result.orNil(), common
+}
@@ -0,0 +1,478 @@
+package champ
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestAlgebra(t *testing.T) {
+	fromRange := func(start, end int) *Map[string, int] {
+		m := New[string, int]()
+		for i := start; i < end; i++ {
+			m = m.Set(fmt.Sprintf("key%d", i), i)
+		}
+		return m
+	}
+
+	// fromKeys builds the expected map from scratch, which is structurally equal
+	// to any map with the same entries in canonical form.
+	fromKeys := func(keys ...[]int) *Map[string, int] {
+		m := New[string, int]()
+		for _, ks := range keys {
+			for _, i := range ks {
+				m = m.Set(fmt.Sprintf("key%d", i), i)
+			}
+		}
+		return m
+	}
+
+	rangeKeys := func(start, end int) []int {
+		keys := make([]int, 0, end-start)
+		for i := start; i < end; i++ {
+			keys = append(keys, i)
+		}
+		return keys
+	}
+
+	for _, tt := range []struct {
+		name                string
+		maps                func() (*Map[string, int], *Map[string, int])
+		intersect           *Map[string, int]
+		difference          *Map[string, int]
+		symmetricDifference *Map[string, int]
+	}{
+		{
+			name: "empty",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int](), New[string, int]()
+			},
+			intersect:           fromKeys(),
+			difference:          fromKeys(),
+			symmetricDifference: fromKeys(),
+		},
+		{
+			name: "one empty",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return fromRange(0, 3), New[string, int]()
+			},
+			intersect:           fromKeys(),
+			difference:          fromKeys(rangeKeys(0, 3)),
+			symmetricDifference: fromKeys(rangeKeys(0, 3)),
+		},
+		{
+			name: "identical maps",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				m := fromRange(0, 1000)
+				return m, m
+			},
+			intersect:           fromKeys(rangeKeys(0, 1000)),
+			difference:          fromKeys(),
+			symmetricDifference: fromKeys(),
+		},
+		{
+			name: "small overlapping maps",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return fromRange(0, 3), fromRange(2, 5)
+			},
+			intersect:           fromKeys([]int{2}),
+			difference:          fromKeys([]int{0, 1}),
+			symmetricDifference: fromKeys([]int{0, 1, 3, 4}),
+		},
+		{
+			name: "large overlapping maps",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return fromRange(0, 2000), fromRange(1000, 3000)
+			},
+			intersect:           fromKeys(rangeKeys(1000, 2000)),
+			difference:          fromKeys(rangeKeys(0, 1000)),
+			symmetricDifference: fromKeys(rangeKeys(0, 1000), rangeKeys(2000, 3000)),
+		},
+		{
+			name: "versions sharing structure",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				m1 := fromRange(0, 2000)
+				m2 := m1
+				for i := 0; i < 2000; i += 10 {
+					m2 = m2.Delete(fmt.Sprintf("key%d", i))
+				}
+				for i := 2000; i < 2100; i++ {
+					m2 = m2.Set(fmt.Sprintf("key%d", i), i)
+				}
+				return m1, m2
+			},
+			intersect: func() *Map[string, int] {
+				m := fromRange(0, 2000)
+				for i := 0; i < 2000; i += 10 {
+					m = m.Delete(fmt.Sprintf("key%d", i))
+				}
+				return m
+			}(),
+			difference: func() *Map[string, int] {
+				m := New[string, int]()
+				for i := 0; i < 2000; i += 10 {
+					m = m.Set(fmt.Sprintf("key%d", i), i)
+				}
+				return m
+			}(),
+			symmetricDifference: func() *Map[string, int] {
+				m := fromRange(2000, 2100)
+				for i := 0; i < 2000; i += 10 {
+					m = m.Set(fmt.Sprintf("key%d", i), i)
+				}
+				return m
+			}(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m1, m2 := tt.maps()
+
+			for _, result := range []struct {
+				name     string
+				actual   *Map[string, int]
+				expected *Map[string, int]
+			}{
+				{"Intersect", Intersect(m1, m2), tt.intersect},
+				{"Difference", Difference(m1, m2), tt.difference},
+				{"SymmetricDifference", SymmetricDifference(m1, m2), tt.symmetricDifference},
+			} {
+				if result.actual.Len() != result.expected.Len() {
+					t.Errorf("%s() Len() expected %d, actual %d", result.name, result.expected.Len(), result.actual.Len())
+				}
+				if !Equal(result.actual, result.expected) {
+					t.Errorf("%s() result does not match expected", result.name)
+				}
+			}
+		})
+	}
+}
+
+func TestAlgebraKeys(t *testing.T) {
+	m := New[string, int]()
+	for i := range 1000 {
+		m = m.Set(fmt.Sprintf("key%d", i), i)
+	}
+
+	for _, tt := range []struct {
+		name string
+		base *Set[string]
+	}{
+		{name: "shared hash function", base: NewSet[string]()},
+		{name: "different hash functions", base: NewSetWithHasher(func(key string) uint64 { return uint64(len(key)) })},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := tt.base
+			for i := 500; i < 1500; i++ {
+				keys = keys.Add(fmt.Sprintf("key%d", i))
+			}
+
+			intersect := IntersectKeys(m, keys)
+			difference := DifferenceKeys(m, keys)
+			for _, result := range []*Map[string, int]{intersect, difference} {
+				if err := result.Validate(); err != nil {
+					t.Errorf("result is invalid: %v", err)
+				}
+			}
+			if intersect.Len() != 500 {
+				t.Errorf("IntersectKeys() Len() expected %d, actual %d", 500, intersect.Len())
+			}
+			if difference.Len() != 500 {
+				t.Errorf("DifferenceKeys() Len() expected %d, actual %d", 500, difference.Len())
+			}
+			for i := range 1000 {
+				key := fmt.Sprintf("key%d", i)
+				if _, ok := intersect.Get(key); ok != (i >= 500) {
+					t.Errorf("IntersectKeys() Get(%q) expected ok=%v", key, i >= 500)
+				}
+				if _, ok := difference.Get(key); ok != (i < 500) {
+					t.Errorf("DifferenceKeys() Get(%q) expected ok=%v", key, i < 500)
+				}
+			}
+
+			if actual := DifferenceKeys(m, tt.base.Add("missing")); actual != m {
+				t.Error("DifferenceKeys() without removed keys returned a new map")
+			}
+			all := keys
+			for k := range m.Keys() {
+				all = all.Add(k)
+			}
+			if actual := IntersectKeys(m, all); actual != m {
+				t.Error("IntersectKeys() keeping every key returned a new map")
+			}
+		})
+	}
+}
+
+func TestFilterNode(t *testing.T) {
+	for _, tt := range []struct {
+		name            string
+		node            node[string, int]
+		keep            func(key string, value int) bool
+		expectedRemoved int
+		expected        node[string, int]
+	}{
+		{
+			name: "collapse single-entry child bitmap indexed node",
+			node: &bitmapIndexedNode[string, int]{
+				datamap: 0b00010,
+				nodemap: 0b00001,
+				keys:    []string{"00001"},
+				values:  []int{10},
+				nodes: []node[string, int]{
+					&bitmapIndexedNode[string, int]{
+						datamap: 0b00110,
+						keys:    []string{"0000100000", "0001000000"},
+						values:  []int{100, 200},
+					},
+				},
+			},
+			keep:            func(_ string, v int) bool { return v != 100 },
+			expectedRemoved: 1,
+			expected: &bitmapIndexedNode[string, int]{
+				datamap: 0b00011,
+				keys:    []string{"0001000000", "00001"},
+				values:  []int{200, 10},
+			},
+		},
+		{
+			name: "collision node with single remaining entry",
+			node: &collisionNode[string, int]{
+				keys:   []string{"1", "01", "001"},
+				values: []int{100, 200, 300},
+			},
+			keep:            func(_ string, v int) bool { return v == 200 },
+			expectedRemoved: 2,
+			expected: &bitmapIndexedNode[string, int]{
+				datamap: 0b00001,
+				keys:    []string{"01"},
+				values:  []int{200},
+			},
+		},
+		{
+			name: "remove all entries",
+			node: &collisionNode[string, int]{
+				keys:   []string{"1", "01"},
+				values: []int{100, 200},
+			},
+			keep:            func(string, int) bool { return false },
+			expectedRemoved: 2,
+			expected:        nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, removed := filterNode(tt.node, tt.keep)
+			if removed != tt.expectedRemoved {
+				t.Errorf("filterNode() removed = %d, expected %d", removed, tt.expectedRemoved)
+			}
+			if !equalNode(result, tt.expected) {
+				t.Errorf("filterNode() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAlgebraWithHasher(t *testing.T) {
+	// low entropy hash function producing deep tries and collision nodes
+	lowEntropy := func(key string) uint64 { return uint64(len(key)+int(key[len(key)-1])%3) << 58 }
+
+	for _, tt := range []struct {
+		name string
+		maps func() (*Map[string, int], *Map[string, int])
+	}{
+		{
+			name: "shared hash function",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				m := NewWithHasher[string, int](lowEntropy)
+				return m, m
+			},
+		},
+		{
+			name: "different hash functions",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return NewWithHasher[string, int](lowEntropy), New[string, int]()
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m1, m2 := tt.maps()
+			ref1, ref2 := map[string]int{}, map[string]int{}
+			for i := range 300 {
+				m1 = m1.Set(fmt.Sprintf("k%d", i), i)
+				ref1[fmt.Sprintf("k%d", i)] = i
+			}
+			for i := 200; i < 500; i++ {
+				m2 = m2.Set(fmt.Sprintf("k%d", i), -i)
+				ref2[fmt.Sprintf("k%d", i)] = -i
+			}
+
+			// build expected maps from scratch with the hash function of m1
+			expected := func(keep func(key string) bool, values ...map[string]int) *Map[string, int] {
+				m := m1.empty()
+				for _, ref := range values {
+					for k, v := range ref {
+						if keep(k) {
+							m = m.Set(k, v)
+						}
+					}
+				}
+				return m
+			}
+			in1 := func(key string) bool { _, ok := ref1[key]; return ok }
+			in2 := func(key string) bool { _, ok := ref2[key]; return ok }
+
+			for _, result := range []struct {
+				name     string
+				actual   *Map[string, int]
+				expected *Map[string, int]
+			}{
+				{
+					name:     "Merge",
+					actual:   Merge(m1, m2, nil),
+					expected: expected(func(string) bool { return true }, ref1, ref2),
+				},
+				{
+					name:     "Intersect",
+					actual:   Intersect(m1, m2),
+					expected: expected(in2, ref1),
+				},
+				{
+					name:     "Difference",
+					actual:   Difference(m1, m2),
+					expected: expected(func(k string) bool { return !in2(k) }, ref1),
+				},
+				{
+					name:     "SymmetricDifference",
+					actual:   SymmetricDifference(m1, m2),
+					expected: expected(func(k string) bool { return in1(k) != in2(k) }, ref1, ref2),
+				},
+			} {
+				if result.actual.Len() != result.expected.Len() {
+					t.Errorf("%s() Len() expected %d, actual %d", result.name, result.expected.Len(), result.actual.Len())
+				}
+				// structural comparison also verifies the canonical form
+				if !equalNode(result.actual.root, result.expected.root) {
+					t.Errorf("%s() result does not match expected", result.name)
+				}
+			}
+
+			changes := 0
+			for c := range Diff(m1, m2) {
+				changes++
+				switch c.Kind {
+				case Added:
+					if in1(c.Key) || !in2(c.Key) {
+						t.Errorf("Diff() unexpected change %v", c)
+					}
+				case Removed:
+					if !in1(c.Key) || in2(c.Key) {
+						t.Errorf("Diff() unexpected change %v", c)
+					}
+				case Updated:
+					if ref1[c.Key] != c.OldValue || ref2[c.Key] != c.NewValue {
+						t.Errorf("Diff() unexpected change %v", c)
+					}
+				}
+			}
+			if changes != 500 {
+				t.Errorf("Diff() yielded %d changes, expected %d", changes, 500)
+			}
+		})
+	}
+}
+
+func TestAlgebraCollisionNodes(t *testing.T) {
+	// every key collides, so both tries end in a collision node at the maximum depth
+	collide := func(int) uint64 { return 42 }
+	base := NewWithHasher[int, int](collide)
+	setBase := NewSetWithHasher(collide)
+	build := func(keys ...int) *Map[int, int] {
+		m := base
+		for _, k := range keys {
+			m = m.Set(k, k)
+		}
+		return m
+	}
+
+	for _, tt := range []struct {
+		name                  string
+		keys1, keys2          []int
+		union, intersect      []int
+		difference, symmetric []int
+	}{
+		{
+			name:  "overlapping",
+			keys1: []int{1, 2}, keys2: []int{2, 3},
+			union: []int{1, 2, 3}, intersect: []int{2}, difference: []int{1}, symmetric: []int{1, 3},
+		},
+		{
+			name:  "equal keys",
+			keys1: []int{1, 2}, keys2: []int{2, 1},
+			union: []int{1, 2}, intersect: []int{1, 2}, difference: nil, symmetric: nil,
+		},
+		{
+			name:  "subset",
+			keys1: []int{1, 2, 3}, keys2: []int{1, 2},
+			union: []int{1, 2, 3}, intersect: []int{1, 2}, difference: []int{3}, symmetric: []int{3},
+		},
+		{
+			name:  "disjoint",
+			keys1: []int{1, 2}, keys2: []int{3, 4},
+			union: []int{1, 2, 3, 4}, intersect: nil, difference: []int{1, 2}, symmetric: []int{1, 2, 3, 4},
+		},
+		{
+			name:  "single key",
+			keys1: []int{1}, keys2: []int{1, 2},
+			union: []int{1, 2}, intersect: []int{1}, difference: nil, symmetric: []int{2},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m1, m2 := build(tt.keys1...), build(tt.keys2...)
+			// a set sharing the hash function of the maps is walked together with them
+			keys2 := &Set[int]{hasher: base.hasher}
+			for _, k := range tt.keys2 {
+				keys2 = keys2.Add(k)
+			}
+			s1, s2 := setBase, setBase
+			for _, k := range tt.keys1 {
+				s1 = s1.Add(k)
+			}
+			for _, k := range tt.keys2 {
+				s2 = s2.Add(k)
+			}
+
+			for _, result := range []struct {
+				name     string
+				actual   *Map[int, int]
+				expected *Map[int, int]
+			}{
+				{name: "Merge", actual: Merge(m1, m2, nil), expected: build(tt.union...)},
+				{name: "Intersect", actual: Intersect(m1, m2), expected: build(tt.intersect...)},
+				{name: "Difference", actual: Difference(m1, m2), expected: build(tt.difference...)},
+				{name: "SymmetricDifference", actual: SymmetricDifference(m1, m2), expected: build(tt.symmetric...)},
+				{name: "IntersectKeys", actual: IntersectKeys(m1, keys2), expected: build(tt.intersect...)},
+				{name: "DifferenceKeys", actual: DifferenceKeys(m1, keys2), expected: build(tt.difference...)},
+			} {
+				if err := result.actual.Validate(); err != nil {
+					t.Errorf("%s() result is invalid: %v", result.name, err)
+				}
+				if !Equal(result.actual, result.expected) {
+					t.Errorf("%s() expected %v, actual %v", result.name, maps.Collect(result.expected.All()), maps.Collect(result.actual.All()))
+				}
+			}
+
+			// sets sharing the hash function are compared structurally, so a non-canonical result is not equal
+			actual := s1.SymmetricDifference(s2)
+			expected := setBase
+			for _, k := range tt.symmetric {
+				expected = expected.Add(k)
+			}
+			if actual.Len() != expected.Len() {
+				t.Errorf("Set.SymmetricDifference() Len() expected %d, actual %d", expected.Len(), actual.Len())
+			}
+			if !actual.Equal(expected) {
+				t.Errorf("Set.SymmetricDifference() expected %v, actual %v", slices.Collect(expected.All()), slices.Collect(actual.All()))
+			}
+		})
+	}
+}
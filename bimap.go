@@ -0,0 +1,105 @@
+package champ
+
+import "iter"
+
+// BiMap represents a persistent one-to-one map, which can be looked up in both directions.
+//
+// Each key maps to exactly one value and each value to exactly one key.
+// The zero value is an empty map ready to use.
+type BiMap[K, V comparable] struct {
+	forward Map[K, V]
+	inverse Map[V, K]
+}
+
+// NewBiMap creates a new empty BiMap.
+func NewBiMap[K, V comparable]() *BiMap[K, V] {
+	return &BiMap[K, V]{}
+}
+
+// Get retrieves a value by key.
+func (b *BiMap[K, V]) Get(key K) (V, bool) {
+	return b.forward.Get(key)
+}
+
+// GetKey retrieves a key by value.
+func (b *BiMap[K, V]) GetKey(value V) (K, bool) {
+	return b.inverse.Get(value)
+}
+
+// Set returns a map in which key and value map to each other.
+//
+// Any previous value of key and any previous key of value are removed,
+// so that the mapping stays one-to-one.
+// If the pair is already present, b itself is returned.
+func (b *BiMap[K, V]) Set(key K, value V) *BiMap[K, V] {
+	forward, inverse := &b.forward, &b.inverse
+
+	if oldValue, ok := forward.Get(key); ok {
+		if oldValue == value {
+			return b
+		}
+		inverse = inverse.Delete(oldValue)
+	}
+	if oldKey, ok := inverse.Get(value); ok {
+		forward = forward.Delete(oldKey)
+	}
+
+	return &BiMap[K, V]{
+		forward: *forward.Set(key, value),
+		inverse: *inverse.Set(value, key),
+	}
+}
+
+// Delete returns a map without key and its value.
+// If key is not present, b itself is returned.
+func (b *BiMap[K, V]) Delete(key K) *BiMap[K, V] {
+	value, ok := b.forward.Get(key)
+	if !ok {
+		return b
+	}
+	return &BiMap[K, V]{
+		forward: *b.forward.Delete(key),
+		inverse: *b.inverse.Delete(value),
+	}
+}
+
+// DeleteValue returns a map without value and its key.
+// If value is not present, b itself is returned.
+func (b *BiMap[K, V]) DeleteValue(value V) *BiMap[K, V] {
+	key, ok := b.inverse.Get(value)
+	if !ok {
+		return b
+	}
+	return &BiMap[K, V]{
+		forward: *b.forward.Delete(key),
+		inverse: *b.inverse.Delete(value),
+	}
+}
+
+// Inverse returns the map from values to keys in O(1), sharing the tries of b.
+func (b *BiMap[K, V]) Inverse() *BiMap[V, K] {
+	return &BiMap[V, K]{
+		forward: b.inverse,
+		inverse: b.forward,
+	}
+}
+
+// Len returns the number of entries
+func (b *BiMap[K, V]) Len() int {
+	return b.forward.Len()
+}
+
+// All returns an iterator over key-value pairs.
+func (b *BiMap[K, V]) All() iter.Seq2[K, V] {
+	return b.forward.All()
+}
+
+// Keys returns an iterator over the keys.
+func (b *BiMap[K, V]) Keys() iter.Seq[K] {
+	return b.forward.Keys()
+}
+
+// Values returns an iterator over the values.
+func (b *BiMap[K, V]) Values() iter.Seq[V] {
+	return b.forward.Values()
+}
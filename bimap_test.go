@@ -0,0 +1,134 @@
+package champ
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBiMap(t *testing.T) {
+	// check verifies that both directions agree with the expected pairs.
+	check := func(t *testing.T, b *BiMap[int, string], expected map[int]string) {
+		t.Helper()
+		if b.Len() != len(expected) {
+			t.Errorf("Len() expected %d, actual %d", len(expected), b.Len())
+		}
+		if b.inverse.Len() != len(expected) {
+			t.Errorf("inverse Len() expected %d, actual %d", len(expected), b.inverse.Len())
+		}
+		for k, v := range expected {
+			if actual, ok := b.Get(k); !ok || actual != v {
+				t.Errorf("Get(%d) expected (%q, true), actual (%q, %v)", k, v, actual, ok)
+			}
+			if actual, ok := b.GetKey(v); !ok || actual != k {
+				t.Errorf("GetKey(%q) expected (%d, true), actual (%d, %v)", v, k, actual, ok)
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		name     string
+		build    func() *BiMap[int, string]
+		expected map[int]string
+	}{
+		{
+			name:     "empty",
+			build:    NewBiMap[int, string],
+			expected: map[int]string{},
+		},
+		{
+			name: "set",
+			build: func() *BiMap[int, string] {
+				return NewBiMap[int, string]().Set(1, "a").Set(2, "b")
+			},
+			expected: map[int]string{1: "a", 2: "b"},
+		},
+		{
+			name: "set evicts previous value of key",
+			build: func() *BiMap[int, string] {
+				return NewBiMap[int, string]().Set(1, "a").Set(1, "b")
+			},
+			expected: map[int]string{1: "b"},
+		},
+		{
+			name: "set evicts previous key of value",
+			build: func() *BiMap[int, string] {
+				return NewBiMap[int, string]().Set(1, "a").Set(2, "a")
+			},
+			expected: map[int]string{2: "a"},
+		},
+		{
+			name: "set evicts on both sides",
+			build: func() *BiMap[int, string] {
+				return NewBiMap[int, string]().Set(1, "a").Set(2, "b").Set(1, "b")
+			},
+			expected: map[int]string{1: "b"},
+		},
+		{
+			name: "delete",
+			build: func() *BiMap[int, string] {
+				return NewBiMap[int, string]().Set(1, "a").Set(2, "b").Delete(1)
+			},
+			expected: map[int]string{2: "b"},
+		},
+		{
+			name: "delete value",
+			build: func() *BiMap[int, string] {
+				return NewBiMap[int, string]().Set(1, "a").Set(2, "b").DeleteValue("b")
+			},
+			expected: map[int]string{1: "a"},
+		},
+		{
+			name: "many entries",
+			build: func() *BiMap[int, string] {
+				b := NewBiMap[int, string]()
+				for i := range 1000 {
+					b = b.Set(i, fmt.Sprint(i))
+				}
+				for i := range 500 {
+					b = b.Set(i, fmt.Sprint(i+500)) // evicts the key i+500
+				}
+				return b
+			},
+			expected: func() map[int]string {
+				m := map[int]string{}
+				for i := range 500 {
+					m[i] = fmt.Sprint(i + 500)
+				}
+				return m
+			}(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			check(t, tt.build(), tt.expected)
+		})
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		var b BiMap[int, string]
+		b1 := b.Set(1, "a")
+		if b2 := b1.Set(1, "a"); b2 != b1 {
+			t.Error("Set() of an existing pair returned a new map")
+		}
+		if b2 := b1.Delete(2); b2 != b1 {
+			t.Error("Delete() of a missing key returned a new map")
+		}
+		if b2 := b1.DeleteValue("b"); b2 != b1 {
+			t.Error("DeleteValue() of a missing value returned a new map")
+		}
+	})
+
+	t.Run("inverse", func(t *testing.T) {
+		b := NewBiMap[int, string]().Set(1, "a").Set(2, "b")
+		inv := b.Inverse()
+		if v, ok := inv.Get("a"); !ok || v != 1 {
+			t.Errorf("Inverse().Get(%q) expected (1, true), actual (%d, %v)", "a", v, ok)
+		}
+
+		inv = inv.Set("c", 1)
+		if _, ok := inv.Get("a"); ok {
+			t.Error("Inverse().Set() did not evict the previous value")
+		}
+		check(t, inv.Inverse(), map[int]string{1: "c", 2: "b"})
+		check(t, b, map[int]string{1: "a", 2: "b"})
+	})
+}
@@ -0,0 +1,132 @@
+package champ
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// binaryVersion is the first byte of the binary encoding of a map.
+const binaryVersion = 1
+
+// MarshalBinary implements encoding.BinaryMarshaler, and thereby gob.GobEncoder support,
+// encoding keys and values with DefaultCodec.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	return m.MarshalBinaryWith(DefaultCodec[K](), DefaultCodec[V]())
+}
+
+// AppendBinary implements encoding.BinaryAppender.
+func (m *Map[K, V]) AppendBinary(b []byte) ([]byte, error) {
+	return m.appendBinary(b, DefaultCodec[K](), DefaultCodec[V]())
+}
+
+// MarshalBinaryWith encodes the map with the given codecs for keys and values.
+//
+// The encoding consists of a version byte and the number of entries,
+// followed by the length-prefixed key and value of every entry in iteration order.
+func (m *Map[K, V]) MarshalBinaryWith(keys Codec[K], values Codec[V]) ([]byte, error) {
+	return m.appendBinary(nil, keys, values)
+}
+
+func (m *Map[K, V]) appendBinary(b []byte, keys Codec[K], values Codec[V]) ([]byte, error) {
+	b = append(b, binaryVersion)
+	b = binary.AppendUvarint(b, uint64(m.size))
+
+	var err error
+	for k, v := range m.All() {
+		if b, err = appendFramed(b, k, keys); err != nil {
+			return nil, fmt.Errorf("champ: encoding key: %w", err)
+		}
+		if b, err = appendFramed(b, v, values); err != nil {
+			return nil, fmt.Errorf("champ: encoding value: %w", err)
+		}
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, and thereby gob.GobDecoder support,
+// decoding keys and values with DefaultCodec.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	return m.UnmarshalBinaryWith(data, DefaultCodec[K](), DefaultCodec[V]())
+}
+
+// UnmarshalBinaryWith decodes data encoded by MarshalBinaryWith with the given codecs.
+//
+// The entries of m are replaced by the decoded ones, and m keeps its hash function.
+// The trie is built bottom-up without copying a path per entry.
+func (m *Map[K, V]) UnmarshalBinaryWith(data []byte, keys Codec[K], values Codec[V]) error {
+	if len(data) == 0 || data[0] != binaryVersion {
+		return errors.New("champ: unsupported binary encoding")
+	}
+	data = data[1:]
+
+	count, n := binary.Uvarint(data)
+	if n <= 0 {
+		return errInvalidEncoding
+	}
+	data = data[n:]
+
+	// Every entry takes at least two bytes, which bounds the preallocation for corrupted counts.
+	entries := make([]buildEntry[K, V], 0, min(count, uint64(len(data)/2)))
+	for range count {
+		var (
+			key   K
+			value V
+			err   error
+		)
+		if key, data, err = decodeFramed(data, keys); err != nil {
+			return fmt.Errorf("champ: decoding key: %w", err)
+		}
+		if value, data, err = decodeFramed(data, values); err != nil {
+			return fmt.Errorf("champ: decoding value: %w", err)
+		}
+		entries = append(entries, buildEntry[K, V]{key: key, value: value})
+	}
+	if len(data) != 0 {
+		return errors.New("champ: unexpected trailing data")
+	}
+
+	result := buildMap(m.hasher, entries)
+	if uint64(result.size) != count {
+		return errors.New("champ: duplicate keys")
+	}
+	*m = *result
+	return nil
+}
+
+// appendFramed appends the encoding of v prefixed by its length.
+func appendFramed[T any](b []byte, v T, codec Codec[T]) ([]byte, error) {
+	// Reserve a single byte for the length, which suffices for most keys and values,
+	// and shift the encoding if the length turns out to need more.
+	start := len(b)
+	b = append(b, 0)
+	b, err := codec.Append(b, v)
+	if err != nil {
+		return nil, err
+	}
+
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(b)-start-1))
+	if n > 1 {
+		b = append(b, prefix[1:n]...)
+		copy(b[start+n:], b[start+1:len(b)-n+1])
+	}
+	copy(b[start:], prefix[:n])
+	return b, nil
+}
+
+// decodeFramed decodes a length-prefixed value and returns the remaining data.
+func decodeFramed[T any](data []byte, codec Codec[T]) (T, []byte, error) {
+	var zero T
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > uint64(len(data)-n) {
+		return zero, nil, errInvalidEncoding
+	}
+	data = data[n:]
+
+	v, err := codec.Decode(data[:size:size])
+	if err != nil {
+		return zero, nil, err
+	}
+	return v, data[size:], nil
+}
@@ -0,0 +1,168 @@
+package champ
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		m    func() *Map[string, string]
+	}{
+		{
+			name: "empty map",
+			m:    New[string, string],
+		},
+		{
+			name: "small map",
+			m: func() *Map[string, string] {
+				return New[string, string]().Set("a", "1").Set("b", "2")
+			},
+		},
+		{
+			name: "large map",
+			m: func() *Map[string, string] {
+				m := New[string, string]()
+				for i := range 5000 {
+					m = m.Set(fmt.Sprintf("key%d", i), strconv.Itoa(i))
+				}
+				return m
+			},
+		},
+		{
+			name: "long values",
+			m: func() *Map[string, string] {
+				// lengths around the boundaries of the varint length prefix
+				m := New[string, string]()
+				for _, n := range []int{127, 128, 16383, 16384, 100000} {
+					m = m.Set(strconv.Itoa(n), strings.Repeat("x", n))
+				}
+				return m
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := tt.m()
+			data, err := expected.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() unexpected error: %v", err)
+			}
+
+			var actual Map[string, string]
+			if err := actual.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() unexpected error: %v", err)
+			}
+			if actual.Len() != expected.Len() {
+				t.Errorf("Len() expected %d, actual %d", expected.Len(), actual.Len())
+			}
+			if !Equal(&actual, expected) {
+				t.Error("UnmarshalBinary() result does not match the marshaled map")
+			}
+
+			appended, err := expected.AppendBinary([]byte("prefix"))
+			if err != nil {
+				t.Fatalf("AppendBinary() unexpected error: %v", err)
+			}
+			if !bytes.Equal(appended[len("prefix"):], data) {
+				t.Error("AppendBinary() result does not match MarshalBinary()")
+			}
+		})
+	}
+}
+
+func TestMarshalBinaryGob(t *testing.T) {
+	type snapshot struct {
+		Name    string
+		Entries *Map[int, []string]
+	}
+
+	expected := snapshot{Name: "test", Entries: New[int, []string]()}
+	for i := range 100 {
+		expected.Entries = expected.Entries.Set(i, []string{strconv.Itoa(i)})
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(expected); err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+	var actual snapshot
+	if err := gob.NewDecoder(&buf).Decode(&actual); err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+
+	if actual.Name != expected.Name {
+		t.Errorf("Name expected %q, actual %q", expected.Name, actual.Name)
+	}
+	for i := range 100 {
+		if v, ok := actual.Entries.Get(i); !ok || len(v) != 1 || v[0] != strconv.Itoa(i) {
+			t.Errorf("Get(%d) expected ([%d], true), actual (%v, %v)", i, i, v, ok)
+		}
+	}
+}
+
+// upperCodec stores strings in upper case, to check that custom codecs are used.
+type upperCodec struct{}
+
+func (upperCodec) Append(b []byte, v string) ([]byte, error) {
+	return append(b, strings.ToUpper(v)...), nil
+}
+
+func (upperCodec) Decode(b []byte) (string, error) {
+	return strings.ToLower(string(b)), nil
+}
+
+func TestMarshalBinaryWith(t *testing.T) {
+	m := NewWithSeed[string, int](42).Set("a", 1).Set("b", 2)
+
+	data, err := m.MarshalBinaryWith(upperCodec{}, DefaultCodec[int]())
+	if err != nil {
+		t.Fatalf("MarshalBinaryWith() unexpected error: %v", err)
+	}
+	if !bytes.Contains(data, []byte("A")) || bytes.Contains(data, []byte("a")) {
+		t.Errorf("MarshalBinaryWith() did not use the key codec: %q", data)
+	}
+
+	actual := NewWithSeed[string, int](42)
+	if err := actual.UnmarshalBinaryWith(data, upperCodec{}, DefaultCodec[int]()); err != nil {
+		t.Fatalf("UnmarshalBinaryWith() unexpected error: %v", err)
+	}
+	if !sameHasher(actual, m) || !Equal(actual, m) {
+		t.Error("UnmarshalBinaryWith() result does not match the marshaled map")
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	valid, err := New[string, int]().Set("a", 1).Set("b", 2).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"unsupported version", append([]byte{2}, valid[1:]...)},
+		{"missing count", []byte{binaryVersion}},
+		{"truncated", valid[:len(valid)-1]},
+		{"trailing data", append(bytes.Clone(valid), 0)},
+		{"length exceeding data", []byte{binaryVersion, 1, 100, 'a'}},
+		{"duplicate key", []byte{binaryVersion, 2, 1, 'a', 1, 2, 1, 'a', 1, 4}},
+		{"invalid value", []byte{binaryVersion, 1, 1, 'a', 1, 0x80}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New[string, int]().Set("unchanged", 1)
+			if err := m.UnmarshalBinary(tt.data); err == nil {
+				t.Error("UnmarshalBinary() expected error")
+			}
+			if m.Len() != 1 {
+				t.Error("UnmarshalBinary() modified the map on error")
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package champ
+
+import "iter"
+
+// FromMap creates a map containing the entries of m.
+//
+// The trie is built bottom-up by partitioning the entries by hash level by level,
+// without copying paths, and is identical to the one built by repeated Set.
+func FromMap[K comparable, V any](m map[K]V) *Map[K, V] {
+	entries := make([]buildEntry[K, V], 0, len(m))
+	for k, v := range m {
+		entries = append(entries, buildEntry[K, V]{key: k, value: v})
+	}
+	return buildMap(nil, entries)
+}
+
+// FromSeq2 creates a map containing the entries yielded by seq.
+// If a key is yielded more than once, the last value wins as with repeated Set.
+//
+// See FromMap for how the trie is built.
+func FromSeq2[K comparable, V any](seq iter.Seq2[K, V]) *Map[K, V] {
+	var entries []buildEntry[K, V]
+	for k, v := range seq {
+		entries = append(entries, buildEntry[K, V]{key: k, value: v})
+	}
+	return buildMap(nil, entries)
+}
+
+// FromSlices creates a map associating keys[i] with values[i].
+// If a key appears more than once, the last value wins as with repeated Set.
+// It panics if the slices have different lengths.
+//
+// See FromMap for how the trie is built.
+func FromSlices[K comparable, V any](keys []K, values []V) *Map[K, V] {
+	if len(keys) != len(values) {
+		panic("champ: FromSlices called with slices of different lengths")
+	}
+	entries := make([]buildEntry[K, V], len(keys))
+	for i, k := range keys {
+		entries[i] = buildEntry[K, V]{key: k, value: values[i]}
+	}
+	return buildMap(nil, entries)
+}
+
+// buildEntry is an entry to be placed in the trie by buildMap.
+type buildEntry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+}
+
+// buildMap creates a map with the given hash function holding the entries,
+// which it reorders in place. Later entries win for duplicate keys.
+func buildMap[K comparable, V any](h *hasher[K], entries []buildEntry[K, V]) *Map[K, V] {
+	if len(entries) == 0 {
+		return &Map[K, V]{hasher: h}
+	}
+
+	hashFunc := h.hashFunc()
+	for i := range entries {
+		entries[i].hash = hashFunc(entries[i].key)
+	}
+
+	root, size := buildNode(entries, make([]buildEntry[K, V], len(entries)), 0)
+	return &Map[K, V]{
+		root:   root,
+		size:   size,
+		hasher: h,
+	}
+}
+
+// buildNode builds the subtree at shift holding the entries, which share the hash chunks consumed above shift,
+// and returns it along with the number of distinct keys.
+//
+// The entries are distributed by their chunk at shift into scratch, which must be as long as entries,
+// with a stable counting sort, and each group is built recursively with the roles of the slices swapped.
+// As the sort is stable, duplicate keys stay in the order they were given.
+func buildNode[K comparable, V any](entries, scratch []buildEntry[K, V], shift uint) (node[K, V], int) {
+	var offsets [branchFactor + 1]int
+	for _, e := range entries {
+		offsets[(e.hash>>shift)&bitMask+1]++
+	}
+	data := 0
+	for c := range branchFactor {
+		if offsets[c+1] == 1 {
+			data++
+		}
+		offsets[c+1] += offsets[c]
+	}
+	next := offsets
+	for _, e := range entries {
+		c := (e.hash >> shift) & bitMask
+		scratch[next[c]] = e
+		next[c]++
+	}
+
+	n := &bitmapIndexedNode[K, V]{
+		keys:   make([]K, 0, data),
+		values: make([]V, 0, data),
+	}
+	size := 0
+	for c := range branchFactor {
+		start, end := offsets[c], offsets[c+1]
+		group := scratch[start:end]
+		bit := uint32(1) << c
+
+		switch {
+		case len(group) == 0:
+			continue
+		case len(group) == 1:
+			n.appendData(bit, group[0].key, group[0].value)
+			size++
+			continue
+		case sameHash(group):
+			group = dedupeEntries(group)
+			if len(group) == 1 {
+				n.appendData(bit, group[0].key, group[0].value)
+				size++
+				continue
+			}
+			n.appendNode(bit, buildCollision(group, shift+bitsPerLevel))
+			size += len(group)
+		default:
+			child, count := buildNode(group, entries[start:end], shift+bitsPerLevel)
+			n.appendNode(bit, child)
+			size += count
+		}
+	}
+	return n, size
+}
+
+// buildCollision builds the subtree at shift holding entries with the same hash,
+// which is a chain of single sub-nodes ending in a collision node at the maximum depth.
+func buildCollision[K comparable, V any](entries []buildEntry[K, V], shift uint) node[K, V] {
+	if shift >= maxDepth*bitsPerLevel {
+		keys := make([]K, len(entries))
+		values := make([]V, len(entries))
+		for i, e := range entries {
+			keys[i], values[i] = e.key, e.value
+		}
+		return &collisionNode[K, V]{keys: keys, values: values}
+	}
+	return &bitmapIndexedNode[K, V]{
+		nodemap: uint32(1) << ((entries[0].hash >> shift) & bitMask),
+		nodes:   []node[K, V]{buildCollision(entries, shift+bitsPerLevel)},
+	}
+}
+
+// sameHash reports whether all entries have the same hash.
+func sameHash[K comparable, V any](entries []buildEntry[K, V]) bool {
+	for _, e := range entries[1:] {
+		if e.hash != entries[0].hash {
+			return false
+		}
+	}
+	return true
+}
+
+// dedupeEntries removes duplicate keys from entries in place,
+// keeping the position of the first and the value of the last occurrence of each key.
+func dedupeEntries[K comparable, V any](entries []buildEntry[K, V]) []buildEntry[K, V] {
+	result := entries[:0]
+	for _, e := range entries {
+		found := false
+		for i := range result {
+			if result[i].key == e.key {
+				result[i].value = e.value
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, e)
+		}
+	}
+	return result
+}
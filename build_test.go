@@ -0,0 +1,91 @@
+package champ
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestFromMap(t *testing.T) {
+	for _, size := range []int{0, 1, 2, 31, 32, 33, 1000, 10000} {
+		t.Run(fmt.Sprintf("size %d", size), func(t *testing.T) {
+			entries := map[string]int{}
+			expected := New[string, int]()
+			for i := range size {
+				key := fmt.Sprintf("key%d", i)
+				entries[key] = i
+				expected = expected.Set(key, i)
+			}
+
+			for _, result := range []struct {
+				name   string
+				actual *Map[string, int]
+			}{
+				{"FromMap", FromMap(entries)},
+				{"FromSeq2", FromSeq2(maps.All(entries))},
+				{"FromSlices", FromSlices(slices.Collect(expected.Keys()), slices.Collect(expected.Values()))},
+			} {
+				if result.actual.Len() != size {
+					t.Errorf("%s() Len() expected %d, actual %d", result.name, size, result.actual.Len())
+				}
+				// structural comparison also verifies the canonical form
+				if !equalNode(result.actual.root, expected.root) {
+					t.Errorf("%s() result does not match repeated Set", result.name)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildMap(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		keys   []string
+		values []int
+	}{
+		{
+			name:   "shared hash prefixes",
+			keys:   []string{"0000100001", "0001000001", "00001", "1", "10", "11"},
+			values: []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:   "collisions",
+			keys:   []string{"1", "01", "001", "0001", "10"},
+			values: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:   "duplicate keys",
+			keys:   []string{"1", "10", "1", "10", "11"},
+			values: []int{1, 2, 3, 4, 5},
+		},
+		{
+			name:   "duplicate colliding keys",
+			keys:   []string{"1", "01", "1", "001", "01", "1"},
+			values: []int{1, 2, 3, 4, 5, 6},
+		},
+		{
+			name:   "high bits",
+			keys:   []string{"1" + fmt.Sprintf("%063b", 1), "1" + fmt.Sprintf("%063b", 2), fmt.Sprintf("%064b", 1)},
+			values: []int{1, 2, 3},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hasher[string]{fn: testHashFunc}
+			expected := &Map[string, int]{hasher: h}
+			entries := make([]buildEntry[string, int], len(tt.keys))
+			for i, k := range tt.keys {
+				expected = expected.Set(k, tt.values[i])
+				entries[i] = buildEntry[string, int]{key: k, value: tt.values[i]}
+			}
+
+			actual := buildMap(h, entries)
+			if actual.Len() != expected.Len() {
+				t.Errorf("buildMap() Len() expected %d, actual %d", expected.Len(), actual.Len())
+			}
+			if !equalNode(actual.root, expected.root) {
+				t.Errorf("buildMap() result does not match repeated Set\nactual:\n%s\nexpected:\n%s", actual.root, expected.root)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package champ
+
+// Builder is a mutable map for efficiently applying a batch of edits.
+//
+// Unlike Map, a Builder updates the nodes it owns in place instead of
+// copying the path from the root on every modification.
+// Call Persistent to obtain an immutable Map of the current entries.
+//
+// A Builder must not be used concurrently from multiple goroutines.
+// The zero value is an empty Builder ready to use.
+type Builder[K comparable, V any] struct {
+	edit   *editToken
+	root   node[K, V]
+	size   int
+	hasher *hasher[K]
+}
+
+// NewBuilder creates a new empty Builder.
+func NewBuilder[K comparable, V any]() *Builder[K, V] {
+	return &Builder[K, V]{
+		edit: &editToken{},
+	}
+}
+
+// Transient returns a Builder initialized with the entries of the map.
+// The Builder shares the hash function of the map, which itself is never modified by the Builder.
+func (m *Map[K, V]) Transient() *Builder[K, V] {
+	return &Builder[K, V]{
+		edit:   &editToken{},
+		root:   m.root,
+		size:   m.size,
+		hasher: m.hasher,
+	}
+}
+
+// Get retrieves a value by key.
+func (b *Builder[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if b.root == nil {
+		return zero, false
+	}
+	return nodeGet(b.root, key, b.hasher.hash(key), 0)
+}
+
+// Set sets or updates a key-value pair in place.
+func (b *Builder[K, V]) Set(key K, value V) {
+	if b.edit == nil {
+		b.edit = &editToken{}
+	}
+
+	h := b.hasher.hash(key)
+
+	if b.root == nil {
+		b.root = &bitmapIndexedNode[K, V]{
+			datamap: uint32(1 << (h & bitMask)),
+			keys:    []K{key},
+			values:  []V{value},
+			edit:    b.edit,
+		}
+		b.size = 1
+		return
+	}
+
+	root, added := nodeSetMut(b.root, b.edit, key, value, h, 0, b.hasher.hashFunc())
+	b.root = root
+	if added {
+		b.size++
+	}
+}
+
+// Delete removes a key in place.
+func (b *Builder[K, V]) Delete(key K) {
+	if b.root == nil {
+		return
+	}
+	if b.edit == nil {
+		b.edit = &editToken{}
+	}
+
+	root, deleted := nodeDelMut(b.root, b.edit, key, b.hasher.hash(key), 0)
+	if !deleted {
+		return
+	}
+	b.root = root
+	b.size--
+}
+
+// Len returns the number of entries
+func (b *Builder[K, V]) Len() int {
+	return b.size
+}
+
+// Persistent returns an immutable Map containing the current entries.
+//
+// The Builder remains usable afterwards.
+// Subsequent edits copy the shared nodes again and never affect the returned Map.
+func (b *Builder[K, V]) Persistent() *Map[K, V] {
+	// Revoke ownership of all nodes reachable from the returned map.
+	b.edit = &editToken{}
+	return &Map[K, V]{
+		root:   b.root,
+		size:   b.size,
+		hasher: b.hasher,
+	}
+}
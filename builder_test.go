@@ -0,0 +1,103 @@
+package champ
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("basic operations", func(t *testing.T) {
+		var b Builder[string, int]
+
+		b.Set("key1", 10)
+		b.Set("key2", 20)
+		b.Set("key1", 100)
+		b.Delete("key2")
+		b.Delete("key3")
+
+		if b.Len() != 1 {
+			t.Fatalf("Len() expected %d, actual %d", 1, b.Len())
+		}
+		if v, ok := b.Get("key1"); !ok || v != 100 {
+			t.Fatalf("Get(%q) expected (%d, true), actual (%d, %v)", "key1", 100, v, ok)
+		}
+		if _, ok := b.Get("key2"); ok {
+			t.Fatalf("Get(%q) expected ok=false", "key2")
+		}
+
+		expected := New[string, int]().Set("key1", 100)
+		if !Equal(b.Persistent(), expected) {
+			t.Error("Persistent() map does not match expected")
+		}
+	})
+
+	t.Run("large map", func(t *testing.T) {
+		const n = 2048
+
+		b := NewBuilder[string, int]()
+		expected := New[string, int]()
+		for i := range n {
+			b.Set(fmt.Sprintf("key%d", i), i)
+			expected = expected.Set(fmt.Sprintf("key%d", i), i)
+		}
+		for i := 0; i < n; i += 3 {
+			b.Delete(fmt.Sprintf("key%d", i))
+			expected = expected.Delete(fmt.Sprintf("key%d", i))
+		}
+
+		m := b.Persistent()
+		if m.Len() != expected.Len() {
+			t.Fatalf("Len() expected %d, actual %d", expected.Len(), m.Len())
+		}
+		if !Equal(m, expected) {
+			t.Error("Persistent() map does not match map built with Set and Delete")
+		}
+
+		for i := range n {
+			b.Delete(fmt.Sprintf("key%d", i))
+		}
+		if b.Len() != 0 {
+			t.Fatalf("Len() expected %d, actual %d", 0, b.Len())
+		}
+		if !Equal(b.Persistent(), New[string, int]()) {
+			t.Error("Persistent() map is not empty")
+		}
+	})
+}
+
+func TestBuilderImmutability(t *testing.T) {
+	const n = 1024
+
+	m1 := New[string, int]()
+	for i := range n {
+		m1 = m1.Set(fmt.Sprintf("key%d", i), i)
+	}
+	snapshot := m1.Set("sentinel", 0).Delete("sentinel")
+
+	// edits through the builder must not affect the source map
+	b := m1.Transient()
+	for i := range n {
+		b.Set(fmt.Sprintf("key%d", i), -i)
+	}
+	b.Delete("key0")
+	if !Equal(m1, snapshot) {
+		t.Error("Transient() builder modified the source map")
+	}
+
+	// edits after Persistent must not affect the returned map
+	m2 := b.Persistent()
+	expected := m1.Delete("key0")
+	for i := 1; i < n; i++ {
+		expected = expected.Set(fmt.Sprintf("key%d", i), -i)
+	}
+	for i := range n {
+		b.Set(fmt.Sprintf("key%d", i), i*2)
+	}
+	b.Set("key", 1)
+	if !Equal(m2, expected) {
+		t.Error("Builder modified a map returned by Persistent()")
+	}
+	if m2.Len() != n-1 {
+		t.Errorf("Len() expected %d, actual %d", n-1, m2.Len())
+	}
+}
@@ -0,0 +1,161 @@
+package champ
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"math"
+	"reflect"
+)
+
+// Codec encodes and decodes keys or values of type T for the binary encoding of maps.
+//
+// Encoded values are length-prefixed by the map encoding,
+// so Decode receives exactly the bytes appended by Append.
+type Codec[T any] interface {
+	// Append appends the encoding of v to b and returns the extended buffer.
+	Append(b []byte, v T) ([]byte, error)
+	// Decode decodes a value from b.
+	Decode(b []byte) (T, error)
+}
+
+var (
+	binaryMarshalerType   = reflect.TypeFor[encoding.BinaryMarshaler]()
+	binaryUnmarshalerType = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+)
+
+var errInvalidEncoding = errors.New("champ: invalid encoding")
+
+// DefaultCodec returns the codec used by MarshalBinary and UnmarshalBinary.
+//
+// Types implementing encoding.BinaryMarshaler and encoding.BinaryUnmarshaler use them.
+// Booleans, numbers, strings and byte slices, including named types of them, use a compact encoding.
+// Any other type is encoded with encoding/gob.
+func DefaultCodec[T any]() Codec[T] {
+	t := reflect.TypeFor[T]()
+	if t.Implements(binaryMarshalerType) && reflect.PointerTo(t).Implements(binaryUnmarshalerType) {
+		return binaryMarshalerCodec[T]{}
+	}
+	switch t.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128,
+		reflect.String:
+		return kindCodec[T]{}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return kindCodec[T]{}
+		}
+	}
+	return gobCodec[T]{}
+}
+
+// kindCodec encodes values by their reflect.Kind.
+type kindCodec[T any] struct{}
+
+func (kindCodec[T]) Append(b []byte, v T) ([]byte, error) {
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return binary.AppendVarint(b, rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(b, rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(rv.Float())), nil
+	case reflect.Complex64, reflect.Complex128:
+		c := rv.Complex()
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(real(c)))
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(imag(c))), nil
+	case reflect.String:
+		return append(b, rv.String()...), nil
+	default:
+		return append(b, rv.Bytes()...), nil
+	}
+}
+
+func (kindCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.Bool:
+		if len(b) != 1 || b[0] > 1 {
+			return v, errInvalidEncoding
+		}
+		rv.SetBool(b[0] == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, n := binary.Varint(b)
+		if n <= 0 || n != len(b) || rv.OverflowInt(x) {
+			return v, errInvalidEncoding
+		}
+		rv.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, n := binary.Uvarint(b)
+		if n <= 0 || n != len(b) || rv.OverflowUint(x) {
+			return v, errInvalidEncoding
+		}
+		rv.SetUint(x)
+	case reflect.Float32, reflect.Float64:
+		if len(b) != 8 {
+			return v, errInvalidEncoding
+		}
+		rv.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	case reflect.Complex64, reflect.Complex128:
+		if len(b) != 16 {
+			return v, errInvalidEncoding
+		}
+		rv.SetComplex(complex(
+			math.Float64frombits(binary.LittleEndian.Uint64(b[:8])),
+			math.Float64frombits(binary.LittleEndian.Uint64(b[8:])),
+		))
+	case reflect.String:
+		rv.SetString(string(b))
+	default:
+		rv.SetBytes(bytes.Clone(b))
+	}
+	return v, nil
+}
+
+// binaryMarshalerCodec encodes values with their MarshalBinary and UnmarshalBinary methods.
+type binaryMarshalerCodec[T any] struct{}
+
+func (binaryMarshalerCodec[T]) Append(b []byte, v T) ([]byte, error) {
+	if a, ok := any(v).(encoding.BinaryAppender); ok {
+		return a.AppendBinary(b)
+	}
+	data, err := any(v).(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(b, data...), nil
+}
+
+func (binaryMarshalerCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	err := any(&v).(encoding.BinaryUnmarshaler).UnmarshalBinary(b)
+	return v, err
+}
+
+// gobCodec encodes each value as a standalone gob stream.
+type gobCodec[T any] struct{}
+
+func (gobCodec[T]) Append(b []byte, v T) ([]byte, error) {
+	buf := bytes.NewBuffer(b)
+	if err := gob.NewEncoder(buf).Encode(&v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v)
+	return v, err
+}
@@ -0,0 +1,100 @@
+package champ
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDefaultCodec(t *testing.T) {
+	type name string
+	type point struct {
+		X, Y int
+		Tags []string
+	}
+
+	for _, tt := range []struct {
+		name      string
+		roundTrip func() (expected, actual any, err error)
+	}{
+		{"bool", roundTrip(true)},
+		{"int", roundTrip(-12345)},
+		{"int8 min", roundTrip(int8(math.MinInt8))},
+		{"uint64 max", roundTrip(uint64(math.MaxUint64))},
+		{"float32", roundTrip(float32(1.5))},
+		{"float64", roundTrip(math.Pi)},
+		{"complex128", roundTrip(complex(1, -2))},
+		{"string", roundTrip("hello, 世界")},
+		{"empty string", roundTrip("")},
+		{"named string", roundTrip(name("named"))},
+		{"bytes", roundTrip([]byte{0, 1, 2})},
+		{"binary marshaler", roundTrip(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC))},
+		{"gob", roundTrip(point{X: 1, Y: -1, Tags: []string{"a"}})},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			expected, actual, err := tt.roundTrip()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("Decode() expected %v, actual %v", expected, actual)
+			}
+		})
+	}
+
+	t.Run("compact integers", func(t *testing.T) {
+		b, _ := DefaultCodec[int]().Append(nil, 1)
+		if len(b) != 1 {
+			t.Errorf("Append() expected 1 byte, actual %d", len(b))
+		}
+	})
+
+	t.Run("decoded bytes do not alias the input", func(t *testing.T) {
+		data := []byte{1, 2, 3}
+		v, _ := DefaultCodec[[]byte]().Decode(data)
+		data[0] = 0
+		if v[0] != 1 {
+			t.Error("Decode() result aliases the input")
+		}
+	})
+
+	for _, tt := range []struct {
+		name   string
+		decode func() error
+	}{
+		{"invalid bool", func() error { _, err := DefaultCodec[bool]().Decode([]byte{2}); return err }},
+		{"int8 overflow", func() error {
+			b, _ := DefaultCodec[int]().Append(nil, 128)
+			_, err := DefaultCodec[int8]().Decode(b)
+			return err
+		}},
+		{"truncated varint", func() error { _, err := DefaultCodec[uint]().Decode([]byte{0x80}); return err }},
+		{"trailing bytes", func() error { _, err := DefaultCodec[int]().Decode([]byte{1, 1}); return err }},
+		{"short float", func() error { _, err := DefaultCodec[float64]().Decode([]byte{1, 2, 3}); return err }},
+		{"invalid gob", func() error { _, err := DefaultCodec[struct{ A int }]().Decode([]byte{1, 2, 3}); return err }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.decode(); err == nil {
+				t.Error("Decode() expected error")
+			}
+		})
+	}
+}
+
+func roundTrip[T any](v T) func() (expected, actual any, err error) {
+	return func() (any, any, error) {
+		codec := DefaultCodec[T]()
+		// append after existing data to check that the buffer is extended
+		b, err := codec.Append([]byte("prefix"), v)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !bytes.HasPrefix(b, []byte("prefix")) {
+			return nil, nil, errInvalidEncoding
+		}
+		actual, err := codec.Decode(b[len("prefix"):])
+		return v, actual, err
+	}
+}
@@ -0,0 +1,246 @@
+package champ
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/maphash"
+	"math"
+)
+
+// Cursor is a resumable position in a map.
+//
+// A cursor visits the entries in hash order, which follows the hash path of the keys
+// regardless of the depth they are stored at, and differs from the order of All.
+// Its position can be saved with Token and restored with Map.Seek on the same or a later version of the map,
+// so that a large map can be paged through across requests:
+// entries present in every version are returned exactly once,
+// and entries added or removed between pages are returned at most once.
+//
+// Keys with identical hashes are visited in insertion order,
+// so adding or removing such keys between pages may repeat or skip some of them.
+type Cursor[K comparable, V any] struct {
+	m     *Map[K, V]
+	stack []cursorFrame[K, V]
+	pos   cursorPos
+
+	last   K    // last returned key
+	hashed bool // whether pos.hash is the hash of last
+}
+
+// cursorFrame is a node on the path of a cursor along with the entries left to visit.
+type cursorFrame[K comparable, V any] struct {
+	node      *bitmapIndexedNode[K, V]
+	bits      uint32 // positions of node left to visit
+	collision *collisionNode[K, V]
+	next      int // index of the next entry of collision
+}
+
+// cursorPos is the position of a cursor, which is all a token records.
+type cursorPos struct {
+	state cursorState
+	hash  uint64 // hash of the last returned key
+	count int    // number of returned keys with that hash
+}
+
+type cursorState byte
+
+const (
+	cursorStart cursorState = iota
+	cursorMiddle
+	cursorDone
+)
+
+const (
+	cursorTokenVersion = 1
+	cursorTokenHeader  = 18 // version, state, fingerprint and hash, followed by the count
+)
+
+// Cursor returns a cursor positioned before the first entry of the map.
+func (m *Map[K, V]) Cursor() *Cursor[K, V] {
+	c := &Cursor[K, V]{m: m}
+	c.seek()
+	return c
+}
+
+// Seek returns a cursor positioned after the entries returned before the token was created.
+// It returns an error if the token is malformed or was created by a map with a different hash function.
+//
+// Tokens of maps created by NewWithSeed with the same seed can be restored across processes.
+// Tokens of maps using the default hash function are only valid within the process.
+func (m *Map[K, V]) Seek(token string) (*Cursor[K, V], error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) < cursorTokenHeader || data[0] != cursorTokenVersion || cursorState(data[1]) > cursorDone {
+		return nil, errors.New("champ: invalid cursor token")
+	}
+	if binary.LittleEndian.Uint64(data[2:]) != m.hasher.fingerprint() {
+		return nil, errors.New("champ: cursor token from a map with a different hash function")
+	}
+	count, n := binary.Uvarint(data[cursorTokenHeader:])
+	if n <= 0 || cursorTokenHeader+n != len(data) {
+		return nil, errors.New("champ: invalid cursor token")
+	}
+
+	c := &Cursor[K, V]{
+		m: m,
+		pos: cursorPos{
+			state: cursorState(data[1]),
+			hash:  binary.LittleEndian.Uint64(data[10:]),
+			count: int(min(count, math.MaxInt)),
+		},
+		hashed: true,
+	}
+	if c.pos.state != cursorDone {
+		c.seek()
+	}
+	return c, nil
+}
+
+// Next returns the next entry and advances the cursor, or returns false if there are no more entries.
+func (c *Cursor[K, V]) Next() (K, V, bool) {
+	for len(c.stack) > 0 {
+		top := &c.stack[len(c.stack)-1]
+
+		if top.collision != nil {
+			if top.next < len(top.collision.keys) {
+				k, v := top.collision.keys[top.next], top.collision.values[top.next]
+				top.next++
+				c.advance(k, top.next)
+				return k, v, true
+			}
+			c.stack = c.stack[:len(c.stack)-1]
+			continue
+		}
+
+		if top.bits == 0 {
+			c.stack = c.stack[:len(c.stack)-1]
+			continue
+		}
+		n := top.node
+		bit := top.bits & -top.bits
+		top.bits &^= bit
+
+		if n.datamap&bit != 0 {
+			idx := popcount(n.datamap & (bit - 1))
+			c.advance(n.keys[idx], 1)
+			return n.keys[idx], n.values[idx], true
+		}
+		c.push(n.nodes[popcount(n.nodemap&(bit-1))], 0)
+	}
+
+	c.pos = cursorPos{state: cursorDone}
+	c.hashed = true
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// Token returns an opaque token recording the position of the cursor, to be restored by Map.Seek.
+func (c *Cursor[K, V]) Token() string {
+	if !c.hashed {
+		c.pos.hash = c.m.hasher.hash(c.last)
+		c.hashed = true
+	}
+
+	b := make([]byte, cursorTokenHeader, cursorTokenHeader+binary.MaxVarintLen64)
+	b[0] = cursorTokenVersion
+	b[1] = byte(c.pos.state)
+	binary.LittleEndian.PutUint64(b[2:], c.m.hasher.fingerprint())
+	binary.LittleEndian.PutUint64(b[10:], c.pos.hash)
+	b = binary.AppendUvarint(b, uint64(c.pos.count))
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// advance records key as the last returned one, being the count-th returned key with its hash.
+// The hash is computed lazily by Token.
+func (c *Cursor[K, V]) advance(key K, count int) {
+	c.last = key
+	c.hashed = false
+	c.pos.state = cursorMiddle
+	c.pos.count = count
+}
+
+// push pushes n onto the stack with all its entries left to visit,
+// or with the first skip entries visited for a collision node.
+func (c *Cursor[K, V]) push(n node[K, V], skip int) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		c.stack = append(c.stack, cursorFrame[K, V]{node: n, bits: n.datamap | n.nodemap})
+	case *collisionNode[K, V]:
+		c.stack = append(c.stack, cursorFrame[K, V]{collision: n, next: min(skip, len(n.keys))})
+	}
+}
+
+// seek builds the stack to visit the entries which come after the position of the cursor.
+func (c *Cursor[K, V]) seek() {
+	if c.m.root == nil {
+		return
+	}
+	if c.pos.state == cursorStart {
+		c.push(c.m.root, 0)
+		return
+	}
+
+	n := c.m.root
+	for shift := uint(0); ; shift += bitsPerLevel {
+		switch nn := n.(type) {
+		case *bitmapIndexedNode[K, V]:
+			bit := uint32(1) << ((c.pos.hash >> shift) & bitMask)
+			// positions after the one on the hash path are left to visit
+			frame := cursorFrame[K, V]{node: nn, bits: (nn.datamap | nn.nodemap) &^ (bit<<1 - 1)}
+
+			if nn.datamap&bit != 0 {
+				// The entry on the hash path is left to visit if its hash comes after the position.
+				// An entry with the same hash has been returned, as it cannot share it with another key.
+				idx := popcount(nn.datamap & (bit - 1))
+				if compareHashPath(c.m.hasher.hash(nn.keys[idx]), c.pos.hash) > 0 {
+					frame.bits |= bit
+				}
+				c.stack = append(c.stack, frame)
+				return
+			}
+			c.stack = append(c.stack, frame)
+			if nn.nodemap&bit == 0 {
+				return
+			}
+			n = nn.nodes[popcount(nn.nodemap&(bit-1))]
+		case *collisionNode[K, V]:
+			switch cmp := compareHashPath(c.m.hasher.hash(nn.keys[0]), c.pos.hash); {
+			case cmp == 0:
+				c.push(nn, c.pos.count)
+			case cmp > 0:
+				c.push(nn, 0)
+			}
+			return
+		}
+	}
+}
+
+// compareHashPath compares two hashes by their hash paths,
+// which is the order entries are laid out in the trie.
+func compareHashPath(h1, h2 uint64) int {
+	for shift := uint(0); shift < 64; shift += bitsPerLevel {
+		c1, c2 := (h1>>shift)&bitMask, (h2>>shift)&bitMask
+		if c1 != c2 {
+			if c1 < c2 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// fingerprint identifies the hash function of h in cursor tokens.
+// Custom hash functions cannot be identified and have a zero fingerprint.
+func (h *hasher[K]) fingerprint() uint64 {
+	const probe = "champ.Cursor"
+	switch {
+	case h == nil:
+		return maphash.String(seed, probe)
+	case h.seeded:
+		return finalize(hashBytes(uint64(h.seed), probe))
+	default:
+		return 0
+	}
+}
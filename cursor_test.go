@@ -0,0 +1,198 @@
+package champ
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// collectCursor returns the keys returned by c until it is exhausted.
+func collectCursor[K comparable, V any](c *Cursor[K, V]) []K {
+	var keys []K
+	for {
+		k, _, ok := c.Next()
+		if !ok {
+			return keys
+		}
+		keys = append(keys, k)
+	}
+}
+
+// collectPages returns the keys returned by cursors restored from the token of the previous page.
+func collectPages[K comparable, V any](t *testing.T, m *Map[K, V], size int) []K {
+	t.Helper()
+
+	var keys []K
+	c := m.Cursor()
+	for {
+		for range size {
+			k, _, ok := c.Next()
+			if !ok {
+				return keys
+			}
+			keys = append(keys, k)
+		}
+
+		var err error
+		if c, err = m.Seek(c.Token()); err != nil {
+			t.Fatalf("Seek() returned error: %v", err)
+		}
+	}
+}
+
+func TestCursor(t *testing.T) {
+	// low entropy hash function producing deep tries and collision nodes
+	lowEntropy := func(key string) uint64 { return uint64(len(key)+int(key[len(key)-1])%3) << 58 }
+
+	for _, tt := range []struct {
+		name string
+		m    func() *Map[string, int]
+	}{
+		{
+			name: "empty",
+			m:    New[string, int],
+		},
+		{
+			name: "small map",
+			m: func() *Map[string, int] {
+				return New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+			},
+		},
+		{
+			name: "large map",
+			m: func() *Map[string, int] {
+				m := New[string, int]()
+				for i := range 5000 {
+					m = m.Set(fmt.Sprintf("k%d", i), i)
+				}
+				return m
+			},
+		},
+		{
+			name: "low entropy hash function",
+			m: func() *Map[string, int] {
+				m := NewWithHasher[string, int](lowEntropy)
+				for i := range 500 {
+					m = m.Set(fmt.Sprintf("k%d", i), i)
+				}
+				return m
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.m()
+			keys := collectCursor(m.Cursor())
+
+			if len(keys) != m.Len() {
+				t.Fatalf("Cursor() returned %d keys, expected %d", len(keys), m.Len())
+			}
+			expected := slices.Collect(m.Keys())
+			slices.SortStableFunc(expected, func(k1, k2 string) int {
+				return compareHashPath(m.hasher.hash(k1), m.hasher.hash(k2))
+			})
+			for i := range keys {
+				// keys with identical hashes are in insertion order
+				if m.hasher.hash(keys[i]) != m.hasher.hash(expected[i]) {
+					t.Fatalf("Cursor() expected keys in hash order, got %q at %d, expected %q", keys[i], i, expected[i])
+				}
+			}
+
+			for _, size := range []int{1, 7, 100} {
+				if actual := collectPages(t, m, size); !slices.Equal(actual, keys) {
+					t.Errorf("pages of %d keys expected %v, actual %v", size, keys, actual)
+				}
+			}
+		})
+	}
+
+	t.Run("later versions", func(t *testing.T) {
+		r := rand.New(rand.NewPCG(1, 2))
+		m := New[int, int]()
+		for i := range 2000 {
+			m = m.Set(i, i)
+		}
+
+		seen := map[int]int{}
+		c := m.Cursor()
+		for {
+			k, _, ok := c.Next()
+			if !ok {
+				break
+			}
+			seen[k]++
+
+			// replace odd keys between pages, pushing existing entries deeper or pulling them up
+			if len(seen)%10 == 0 {
+				for range 20 {
+					k := r.IntN(2000)*2 + 1
+					if r.IntN(2) == 0 {
+						m = m.Set(k, k)
+					} else {
+						m = m.Delete(k)
+					}
+				}
+
+				var err error
+				if c, err = m.Seek(c.Token()); err != nil {
+					t.Fatalf("Seek() returned error: %v", err)
+				}
+			}
+		}
+
+		for k, n := range seen {
+			if n != 1 {
+				t.Errorf("key %d returned %d times", k, n)
+			}
+		}
+		// even keys are present in every version
+		for k := 0; k < 2000; k += 2 {
+			if seen[k] != 1 {
+				t.Errorf("key %d present in every version returned %d times", k, seen[k])
+			}
+		}
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		m := New[int, int]().Set(1, 1)
+		c := m.Cursor()
+		collectCursor(c)
+
+		c, err := m.Set(2, 2).Seek(c.Token())
+		if err != nil {
+			t.Fatalf("Seek() returned error: %v", err)
+		}
+		if _, _, ok := c.Next(); ok {
+			t.Error("Next() of an exhausted cursor expected false")
+		}
+	})
+
+	t.Run("invalid tokens", func(t *testing.T) {
+		m := NewWithSeed[int, int](1).Set(1, 1)
+		c := m.Cursor()
+		c.Next()
+		token := c.Token()
+
+		if _, err := NewWithSeed[int, int](1).Seek(token); err != nil {
+			t.Errorf("Seek() with the same seed returned error: %v", err)
+		}
+		for _, tt := range []struct {
+			name  string
+			m     *Map[int, int]
+			token string
+		}{
+			{name: "different seed", m: NewWithSeed[int, int](2), token: token},
+			{name: "default hash function", m: New[int, int](), token: token},
+			{name: "empty", m: m, token: ""},
+			{name: "not base64", m: m, token: "!"},
+			{name: "truncated", m: m, token: token[:len(token)-2]},
+			{name: "trailing data", m: m, token: token + "AA"},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := tt.m.Seek(tt.token); err == nil {
+					t.Errorf("Seek(%q) expected error", tt.token)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,248 @@
+package champ
+
+import "iter"
+
+// ChangeKind describes how an entry differs between two maps.
+type ChangeKind int
+
+const (
+	// Added indicates an entry present only in the newer map.
+	Added ChangeKind = iota + 1
+	// Removed indicates an entry present only in the older map.
+	Removed
+	// Updated indicates an entry present in both maps with different values.
+	Updated
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "Added"
+	case Removed:
+		return "Removed"
+	case Updated:
+		return "Updated"
+	default:
+		return "ChangeKind(unknown)"
+	}
+}
+
+// Change describes the difference of a single entry between two maps.
+type Change[K comparable, V any] struct {
+	Kind     ChangeKind
+	Key      K
+	OldValue V // zero value if Kind is Added
+	NewValue V // zero value if Kind is Removed
+}
+
+// Diff returns an iterator over the changes turning from into to.
+//
+// Subtrees shared by both maps are skipped without being visited,
+// so diffing two versions derived from each other costs time proportional to the number of changes.
+// If the maps use different hash functions, every entry is looked up in the other map instead.
+func Diff[K, V comparable](from, to *Map[K, V]) iter.Seq[Change[K, V]] {
+	return DiffFunc(from, to, func(v1, v2 V) bool { return v1 == v2 })
+}
+
+// DiffFunc is like Diff but uses eq to compare values.
+func DiffFunc[K comparable, V any](from, to *Map[K, V], eq func(v1, v2 V) bool) iter.Seq[Change[K, V]] {
+	return func(yield func(Change[K, V]) bool) {
+		switch {
+		case from.root == nil && to.root == nil:
+			return
+		case from.root == nil:
+			yieldAll(to.root, Added, yield)
+		case to.root == nil:
+			yieldAll(from.root, Removed, yield)
+		case !sameHasher(from, to):
+			diffLookup(from, to, eq, yield)
+		default:
+			diffNode(from.root, to.root, 0, from.hasher.hashFunc(), eq, yield)
+		}
+	}
+}
+
+// diffLookup yields the changes between two maps by looking up every entry in the other map.
+func diffLookup[K comparable, V any](from, to *Map[K, V], eq func(v1, v2 V) bool, yield func(Change[K, V]) bool) {
+	for k, v1 := range from.All() {
+		v2, ok := to.Get(k)
+		switch {
+		case !ok:
+			if !yield(Change[K, V]{Kind: Removed, Key: k, OldValue: v1}) {
+				return
+			}
+		case !eq(v1, v2):
+			if !yield(Change[K, V]{Kind: Updated, Key: k, OldValue: v1, NewValue: v2}) {
+				return
+			}
+		}
+	}
+	for k, v2 := range to.All() {
+		if _, ok := from.Get(k); !ok {
+			if !yield(Change[K, V]{Kind: Added, Key: k, NewValue: v2}) {
+				return
+			}
+		}
+	}
+}
+
+// diffNode yields the changes between two nodes at the same position of the trie.
+// It returns false if yield stopped the iteration.
+func diffNode[K comparable, V any](
+	n1, n2 node[K, V],
+	shift uint,
+	hashFunc func(key K) uint64,
+	eq func(v1, v2 V) bool,
+	yield func(Change[K, V]) bool,
+) bool {
+	// short-circuit for identical pointers
+	if n1 == n2 {
+		return true
+	}
+
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedNode[K, V]); ok {
+			return diffBitmapIndexedNodes(n1, n2, shift, hashFunc, eq, yield)
+		}
+	}
+
+	// Collision nodes, and nodes of different kinds which never meet in a canonical trie.
+	for k, v1 := range allNode(n1) {
+		if !diffEntry(k, v1, true, n2, hashFunc(k), shift, eq, yield) {
+			return false
+		}
+	}
+	for k, v2 := range allNode(n2) {
+		if _, ok := nodeGet(n1, k, hashFunc(k), shift); !ok {
+			if !yield(Change[K, V]{Kind: Added, Key: k, NewValue: v2}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func diffBitmapIndexedNodes[K comparable, V any](
+	n1, n2 *bitmapIndexedNode[K, V],
+	shift uint,
+	hashFunc func(key K) uint64,
+	eq func(v1, v2 V) bool,
+	yield func(Change[K, V]) bool,
+) bool {
+	for bits := n1.datamap | n1.nodemap | n2.datamap | n2.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		switch {
+		case n1.datamap&bit != 0:
+			idx1 := popcount(n1.datamap & (bit - 1))
+			k1, v1 := n1.keys[idx1], n1.values[idx1]
+
+			switch {
+			case n2.datamap&bit != 0:
+				idx2 := popcount(n2.datamap & (bit - 1))
+				k2, v2 := n2.keys[idx2], n2.values[idx2]
+				if k1 == k2 {
+					if !eq(v1, v2) && !yield(Change[K, V]{Kind: Updated, Key: k1, OldValue: v1, NewValue: v2}) {
+						return false
+					}
+					continue
+				}
+				if !yield(Change[K, V]{Kind: Removed, Key: k1, OldValue: v1}) ||
+					!yield(Change[K, V]{Kind: Added, Key: k2, NewValue: v2}) {
+					return false
+				}
+			case n2.nodemap&bit != 0:
+				child := n2.nodes[popcount(n2.nodemap&(bit-1))]
+				if !diffEntry(k1, v1, true, child, hashFunc(k1), shift+bitsPerLevel, eq, yield) {
+					return false
+				}
+				for k, v := range allNode(child) {
+					if k != k1 && !yield(Change[K, V]{Kind: Added, Key: k, NewValue: v}) {
+						return false
+					}
+				}
+			default:
+				if !yield(Change[K, V]{Kind: Removed, Key: k1, OldValue: v1}) {
+					return false
+				}
+			}
+
+		case n1.nodemap&bit != 0:
+			child := n1.nodes[popcount(n1.nodemap&(bit-1))]
+
+			switch {
+			case n2.datamap&bit != 0:
+				idx2 := popcount(n2.datamap & (bit - 1))
+				k2, v2 := n2.keys[idx2], n2.values[idx2]
+				for k, v := range allNode(child) {
+					if k != k2 && !yield(Change[K, V]{Kind: Removed, Key: k, OldValue: v}) {
+						return false
+					}
+				}
+				if !diffEntry(k2, v2, false, child, hashFunc(k2), shift+bitsPerLevel, eq, yield) {
+					return false
+				}
+			case n2.nodemap&bit != 0:
+				if !diffNode(child, n2.nodes[popcount(n2.nodemap&(bit-1))], shift+bitsPerLevel, hashFunc, eq, yield) {
+					return false
+				}
+			default:
+				if !yieldAll(child, Removed, yield) {
+					return false
+				}
+			}
+
+		case n2.datamap&bit != 0:
+			idx2 := popcount(n2.datamap & (bit - 1))
+			if !yield(Change[K, V]{Kind: Added, Key: n2.keys[idx2], NewValue: n2.values[idx2]}) {
+				return false
+			}
+
+		default:
+			if !yieldAll(n2.nodes[popcount(n2.nodemap&(bit-1))], Added, yield) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// diffEntry yields the change of a single entry compared with its counterpart in n.
+// If old is true, the entry belongs to the older map and n to the newer one, and vice versa.
+func diffEntry[K comparable, V any](
+	key K, value V, old bool,
+	n node[K, V],
+	hash uint64,
+	shift uint,
+	eq func(v1, v2 V) bool,
+	yield func(Change[K, V]) bool,
+) bool {
+	other, ok := nodeGet(n, key, hash, shift)
+	switch {
+	case !ok && old:
+		return yield(Change[K, V]{Kind: Removed, Key: key, OldValue: value})
+	case !ok:
+		return yield(Change[K, V]{Kind: Added, Key: key, NewValue: value})
+	case old && !eq(value, other):
+		return yield(Change[K, V]{Kind: Updated, Key: key, OldValue: value, NewValue: other})
+	case !old && !eq(other, value):
+		return yield(Change[K, V]{Kind: Updated, Key: key, OldValue: other, NewValue: value})
+	}
+	return true
+}
+
+// yieldAll yields every entry of n as a change of the given kind.
+func yieldAll[K comparable, V any](n node[K, V], kind ChangeKind, yield func(Change[K, V]) bool) bool {
+	for k, v := range allNode(n) {
+		c := Change[K, V]{Kind: kind, Key: k}
+		if kind == Removed {
+			c.OldValue = v
+		} else {
+			c.NewValue = v
+		}
+		if !yield(c) {
+			return false
+		}
+	}
+	return true
+}
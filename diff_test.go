@@ -0,0 +1,175 @@
+package champ
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	fromRange := func(start, end int) *Map[string, int] {
+		m := New[string, int]()
+		for i := start; i < end; i++ {
+			m = m.Set(fmt.Sprintf("key%d", i), i)
+		}
+		return m
+	}
+
+	for _, tt := range []struct {
+		name     string
+		maps     func() (*Map[string, int], *Map[string, int])
+		expected []Change[string, int]
+	}{
+		{
+			name: "empty",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int](), New[string, int]()
+			},
+			expected: nil,
+		},
+		{
+			name: "from empty",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int](), New[string, int]().Set("a", 1)
+			},
+			expected: []Change[string, int]{
+				{Kind: Added, Key: "a", NewValue: 1},
+			},
+		},
+		{
+			name: "to empty",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int]().Set("a", 1), New[string, int]()
+			},
+			expected: []Change[string, int]{
+				{Kind: Removed, Key: "a", OldValue: 1},
+			},
+		},
+		{
+			name: "small maps",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				m1 := New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+				m2 := New[string, int]().Set("b", 20).Set("c", 3).Set("d", 4)
+				return m1, m2
+			},
+			expected: []Change[string, int]{
+				{Kind: Removed, Key: "a", OldValue: 1},
+				{Kind: Updated, Key: "b", OldValue: 2, NewValue: 20},
+				{Kind: Added, Key: "d", NewValue: 4},
+			},
+		},
+		{
+			name: "large maps built independently",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				m2 := fromRange(1, 1000).Set("key500", -500)
+				return fromRange(0, 999), m2
+			},
+			expected: []Change[string, int]{
+				{Kind: Removed, Key: "key0", OldValue: 0},
+				{Kind: Updated, Key: "key500", OldValue: 500, NewValue: -500},
+				{Kind: Added, Key: "key999", NewValue: 999},
+			},
+		},
+		{
+			name: "versions sharing structure",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				m1 := fromRange(0, 1000)
+				m2 := m1.Delete("key0").Set("key500", -500).Set("key1000", 1000).Set("key1", 1)
+				return m1, m2
+			},
+			expected: []Change[string, int]{
+				{Kind: Removed, Key: "key0", OldValue: 0},
+				{Kind: Added, Key: "key1000", NewValue: 1000},
+				{Kind: Updated, Key: "key500", OldValue: 500, NewValue: -500},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m1, m2 := tt.maps()
+
+			actual := slices.SortedFunc(Diff(m1, m2), func(a, b Change[string, int]) int {
+				return cmp.Compare(a.Key, b.Key)
+			})
+			if !slices.Equal(actual, tt.expected) {
+				t.Errorf("Diff() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	t.Run("shared subtrees are skipped", func(t *testing.T) {
+		m1 := New[string, int]()
+		for i := range 10000 {
+			m1 = m1.Set(fmt.Sprintf("key%d", i), i)
+		}
+		m2 := m1.Set("key42", -42)
+
+		calls := 0
+		eq := func(v1, v2 int) bool {
+			calls++
+			return v1 == v2
+		}
+
+		changes := slices.Collect(DiffFunc(m1, m2, eq))
+		expected := []Change[string, int]{
+			{Kind: Updated, Key: "key42", OldValue: 42, NewValue: -42},
+		}
+		if !slices.Equal(changes, expected) {
+			t.Errorf("DiffFunc() = %v, expected %v", changes, expected)
+		}
+		// only the entries along the modified path are compared
+		if calls > branchFactor*maxDepth {
+			t.Errorf("DiffFunc() compared %d values, expected at most %d", calls, branchFactor*maxDepth)
+		}
+	})
+
+	t.Run("stop iteration", func(t *testing.T) {
+		m1 := New[string, int]()
+		m2 := New[string, int]()
+		for i := range 100 {
+			m2 = m2.Set(fmt.Sprintf("key%d", i), i)
+		}
+
+		count := 0
+		for range DiffFunc(m1, m2, func(v1, v2 int) bool { return v1 == v2 }) {
+			count++
+			if count == 10 {
+				break
+			}
+		}
+		if count != 10 {
+			t.Errorf("DiffFunc() yielded %d changes, expected %d", count, 10)
+		}
+	})
+}
+
+func TestDiffNode(t *testing.T) {
+	n1 := &collisionNode[string, int]{
+		keys:   []string{"1", "01", "001"},
+		values: []int{100, 200, 300},
+	}
+	n2 := &collisionNode[string, int]{
+		keys:   []string{"001", "0001", "01"},
+		values: []int{300, 400, 250},
+	}
+
+	var actual []Change[string, int]
+	diffNode(n1, n2, 5*13, testHashFunc, func(v1, v2 int) bool { return v1 == v2 }, func(c Change[string, int]) bool {
+		actual = append(actual, c)
+		return true
+	})
+	slices.SortFunc(actual, func(a, b Change[string, int]) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	expected := []Change[string, int]{
+		{Kind: Added, Key: "0001", NewValue: 400},
+		{Kind: Updated, Key: "01", OldValue: 200, NewValue: 250},
+		{Kind: Removed, Key: "1", OldValue: 100},
+	}
+	if !slices.Equal(actual, expected) {
+		t.Errorf("diffNode() = %v, expected %v", actual, expected)
+	}
+}
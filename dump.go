@@ -0,0 +1,173 @@
+package champ
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DumpFormat is the output format of Dump.
+type DumpFormat int
+
+const (
+	// DumpDOT writes a Graphviz DOT graph with a vertex per node,
+	// labeled with the bitmaps and entries of the node, and an edge per child.
+	DumpDOT DumpFormat = iota
+	// DumpJSON writes a JSON object listing the roots of the maps and every node,
+	// with children referring to nodes by their index in the list.
+	DumpJSON
+)
+
+// Dump writes the structure of the trie of m to w in the given format.
+func (m *Map[K, V]) Dump(w io.Writer, format DumpFormat) error {
+	return Dump(w, format, m)
+}
+
+// Dump writes the structure of the tries of the maps to w in the given format.
+//
+// Nodes shared between the maps, or between parts of a map, are written once,
+// so the output shows the structural sharing between versions of a map.
+// Keys and values are written with fmt in DOT and with encoding/json in JSON.
+func Dump[K comparable, V any](w io.Writer, format DumpFormat, maps ...*Map[K, V]) error {
+	d := &dumper[K, V]{ids: make(map[node[K, V]]int)}
+	for _, m := range maps {
+		root := -1
+		if m.root != nil {
+			root = d.add(m.root, 0)
+		}
+		d.roots = append(d.roots, dumpRoot{Size: m.size, Root: root})
+	}
+
+	switch format {
+	case DumpDOT:
+		return d.writeDOT(w)
+	case DumpJSON:
+		return d.writeJSON(w)
+	default:
+		return fmt.Errorf("champ: unknown dump format %d", format)
+	}
+}
+
+// dumper collects the distinct nodes of the maps to dump in preorder.
+type dumper[K comparable, V any] struct {
+	ids   map[node[K, V]]int
+	nodes []dumpNode[K, V]
+	roots []dumpRoot
+}
+
+// dumpRoot is a map in the JSON dump.
+type dumpRoot struct {
+	Size int `json:"size"`
+	Root int `json:"root"` // index of the root node, or -1 for an empty map
+}
+
+// dumpNode is a node in the JSON dump.
+type dumpNode[K comparable, V any] struct {
+	Kind     string            `json:"kind"`
+	Shift    uint              `json:"shift"`
+	Datamap  uint32            `json:"datamap,omitempty"`
+	Nodemap  uint32            `json:"nodemap,omitempty"`
+	Entries  []dumpEntry[K, V] `json:"entries,omitempty"`
+	Children []dumpChild       `json:"children,omitempty"`
+}
+
+type dumpEntry[K comparable, V any] struct {
+	Bit   *int `json:"bit,omitempty"` // nil in collision nodes
+	Key   K    `json:"key"`
+	Value V    `json:"value"`
+}
+
+type dumpChild struct {
+	Bit  int `json:"bit"`
+	Node int `json:"node"`
+}
+
+// add adds the subtree n at shift unless it has been added, and returns the index of n.
+func (d *dumper[K, V]) add(n node[K, V], shift uint) int {
+	if id, ok := d.ids[n]; ok {
+		return id
+	}
+	id := len(d.nodes)
+	d.ids[n] = id
+	d.nodes = append(d.nodes, dumpNode[K, V]{})
+
+	var dn dumpNode[K, V]
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		dn = dumpNode[K, V]{Kind: "bitmap", Shift: shift, Datamap: n.datamap, Nodemap: n.nodemap}
+		i := 0
+		for bits := n.datamap; bits != 0; bits &= bits - 1 {
+			bit := bitIndex(bits & -bits)
+			dn.Entries = append(dn.Entries, dumpEntry[K, V]{Bit: &bit, Key: n.keys[i], Value: n.values[i]})
+			i++
+		}
+		i = 0
+		for bits := n.nodemap; bits != 0; bits &= bits - 1 {
+			dn.Children = append(dn.Children, dumpChild{Bit: bitIndex(bits & -bits), Node: d.add(n.nodes[i], shift+bitsPerLevel)})
+			i++
+		}
+	case *collisionNode[K, V]:
+		dn = dumpNode[K, V]{Kind: "collision", Shift: shift}
+		for i := range n.keys {
+			dn.Entries = append(dn.Entries, dumpEntry[K, V]{Key: n.keys[i], Value: n.values[i]})
+		}
+	}
+	d.nodes[id] = dn
+	return id
+}
+
+// bitIndex returns the position of the single set bit.
+func bitIndex(bit uint32) int {
+	return popcount(bit - 1)
+}
+
+func (d *dumper[K, V]) writeJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(struct {
+		Maps  []dumpRoot       `json:"maps"`
+		Nodes []dumpNode[K, V] `json:"nodes"`
+	}{d.roots, d.nodes})
+}
+
+func (d *dumper[K, V]) writeDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph champ {")
+	fmt.Fprintln(bw, "\tnode [shape=box, fontname=monospace];")
+
+	for i, r := range d.roots {
+		fmt.Fprintf(bw, "\tm%d [label=\"map %d\\nlen %d\", shape=ellipse];\n", i, i, r.Size)
+		if r.Root >= 0 {
+			fmt.Fprintf(bw, "\tm%d -> n%d;\n", i, r.Root)
+		}
+	}
+
+	for id, n := range d.nodes {
+		var label strings.Builder
+		switch n.Kind {
+		case "bitmap":
+			fmt.Fprintf(&label, "shift %d\\ldatamap %032b\\lnodemap %032b\\l", n.Shift, n.Datamap, n.Nodemap)
+		case "collision":
+			fmt.Fprintf(&label, "collision\\l")
+		}
+		for _, e := range n.Entries {
+			if e.Bit != nil {
+				fmt.Fprintf(&label, "[%d] ", *e.Bit)
+			}
+			fmt.Fprintf(&label, "%s => %s\\l", dotEscape(fmt.Sprint(e.Key)), dotEscape(fmt.Sprint(e.Value)))
+		}
+		fmt.Fprintf(bw, "\tn%d [label=\"%s\"];\n", id, label.String())
+
+		for _, c := range n.Children {
+			fmt.Fprintf(bw, "\tn%d -> n%d [label=\"%d\"];\n", id, c.Node, c.Bit)
+		}
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotEscape escapes s for a DOT string.
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
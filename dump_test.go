@@ -0,0 +1,118 @@
+package champ
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	m := NewWithHasher[string, int](testHashFunc).Set("0", 0).Set("1", 1).Set("100000", 2)
+
+	t.Run("DOT", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := m.Dump(&buf, DumpDOT); err != nil {
+			t.Fatalf("Dump() returned error: %v", err)
+		}
+
+		expected := `digraph champ {
+	node [shape=box, fontname=monospace];
+	m0 [label="map 0\nlen 3", shape=ellipse];
+	m0 -> n0;
+	n0 [label="shift 0\ldatamap 00000000000000000000000000000010\lnodemap 00000000000000000000000000000001\l[1] 1 => 1\l"];
+	n0 -> n1 [label="0"];
+	n1 [label="shift 5\ldatamap 00000000000000000000000000000011\lnodemap 00000000000000000000000000000000\l[0] 0 => 0\l[1] 100000 => 2\l"];
+}
+`
+		if actual := buf.String(); actual != expected {
+			t.Errorf("Dump() expected\n%s\nactual\n%s", expected, actual)
+		}
+	})
+
+	t.Run("DOT escaping", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := New[string, string]().Set(`"a\b"`, "c\nd").Dump(&buf, DumpDOT); err != nil {
+			t.Fatalf("Dump() returned error: %v", err)
+		}
+		if expected := `\"a\\b\" => c\nd\l`; !strings.Contains(buf.String(), expected) {
+			t.Errorf("Dump() expected to contain %s, actual\n%s", expected, buf.String())
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := m.Dump(&buf, DumpJSON); err != nil {
+			t.Fatalf("Dump() returned error: %v", err)
+		}
+
+		expected := `{"maps":[{"size":3,"root":0}],"nodes":[` +
+			`{"kind":"bitmap","shift":0,"datamap":2,"nodemap":1,"entries":[{"bit":1,"key":"1","value":1}],"children":[{"bit":0,"node":1}]},` +
+			`{"kind":"bitmap","shift":5,"datamap":3,"entries":[{"bit":0,"key":"0","value":0},{"bit":1,"key":"100000","value":2}]}]}` + "\n"
+		if actual := buf.String(); actual != expected {
+			t.Errorf("Dump() expected\n%s\nactual\n%s", expected, actual)
+		}
+	})
+
+	t.Run("shared nodes", func(t *testing.T) {
+		// the colliding keys add a chain of nodes shared by both versions
+		m1 := m.Set("11", 3).Set("011", 4)
+		m2 := m1.Set("1", 10)
+		maps := []*Map[string, int]{m1, m2, New[string, int]()}
+
+		var buf bytes.Buffer
+		if err := Dump(&buf, DumpJSON, maps...); err != nil {
+			t.Fatalf("Dump() returned error: %v", err)
+		}
+		var dump struct {
+			Maps []struct {
+				Size int `json:"size"`
+				Root int `json:"root"`
+			} `json:"maps"`
+			Nodes []struct {
+				Kind     string `json:"kind"`
+				Children []struct {
+					Node int `json:"node"`
+				} `json:"children"`
+			} `json:"nodes"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &dump); err != nil {
+			t.Fatalf("json.Unmarshal() returned error: %v", err)
+		}
+
+		if expected := SharedStats(maps...).Nodes; len(dump.Nodes) != expected {
+			t.Errorf("Dump() wrote %d nodes, expected %d distinct nodes", len(dump.Nodes), expected)
+		}
+		for i, r := range dump.Maps {
+			if r.Size != maps[i].Len() {
+				t.Errorf("Dump() map %d size expected %d, actual %d", i, maps[i].Len(), r.Size)
+			}
+		}
+		if dump.Maps[2].Root != -1 {
+			t.Errorf("Dump() empty map root expected -1, actual %d", dump.Maps[2].Root)
+		}
+		collisions := 0
+		for _, n := range dump.Nodes {
+			if n.Kind == "collision" {
+				collisions++
+			}
+		}
+		if collisions != 1 {
+			t.Errorf("Dump() wrote %d collision nodes, expected 1", collisions)
+		}
+
+		buf.Reset()
+		if err := Dump(&buf, DumpDOT, maps...); err != nil {
+			t.Fatalf("Dump() returned error: %v", err)
+		}
+		if count := strings.Count(buf.String(), "label=\"collision"); count != 1 {
+			t.Errorf("Dump() drew %d collision nodes, expected 1", count)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		if err := m.Dump(&bytes.Buffer{}, DumpFormat(-1)); err == nil {
+			t.Error("Dump() with an unknown format expected error")
+		}
+	})
+}
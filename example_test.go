@@ -2,6 +2,8 @@ package champ_test
 
 import (
 	"fmt"
+	"hash/fnv"
+	"slices"
 
 	"github.com/shota3506/go-champ"
 )
@@ -31,3 +33,118 @@ func ExampleMap() {
 	// banana: 3
 	// Size: 2
 }
+
+func ExampleBuilder() {
+	b := champ.NewBuilder[string, int]()
+
+	// Apply a batch of edits in place
+	for i, key := range []string{"apple", "banana", "cherry"} {
+		b.Set(key, i)
+	}
+	b.Delete("banana")
+
+	// Freeze the entries into an immutable map
+	m := b.Persistent()
+
+	fmt.Printf("Size: %d\n", m.Len())
+
+	// Output:
+	// Size: 2
+}
+
+func ExampleSet() {
+	s := champ.NewSet("apple", "banana")
+
+	s = s.Add("cherry")
+	s = s.Remove("apple")
+
+	fmt.Println(s.Contains("banana"))
+	fmt.Println(s.Contains("apple"))
+
+	other := champ.NewSet("cherry", "durian")
+	fmt.Printf("Union: %d\n", s.Union(other).Len())
+	fmt.Printf("Intersection: %d\n", s.Intersection(other).Len())
+
+	// Output:
+	// true
+	// false
+	// Union: 3
+	// Intersection: 1
+}
+
+func ExampleDiff() {
+	v1 := champ.New[string, int]().Set("apple", 5).Set("banana", 3)
+	v2 := v1.Set("banana", 4)
+
+	for c := range champ.Diff(v1, v2) {
+		fmt.Printf("%s %s: %d -> %d\n", c.Kind, c.Key, c.OldValue, c.NewValue)
+	}
+
+	// Output:
+	// Updated banana: 3 -> 4
+}
+
+// Path is a non-comparable key type implementing champ.Hashable.
+type Path []string
+
+func (p Path) Hash() uint64 {
+	h := fnv.New64a()
+	for _, s := range p {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return h.Sum64()
+}
+
+func (p Path) Equal(other Path) bool {
+	return slices.Equal(p, other)
+}
+
+func ExampleHashMap() {
+	m := champ.NewHashMap[Path, int]()
+
+	m = m.Set(Path{"usr", "bin"}, 1)
+	m = m.Set(Path{"usr", "lib"}, 2)
+
+	if value, ok := m.Get(Path{"usr", "lib"}); ok {
+		fmt.Printf("usr/lib: %d\n", value)
+	}
+	fmt.Printf("Size: %d\n", m.Len())
+
+	// Output:
+	// usr/lib: 2
+	// Size: 2
+}
+
+func ExampleCursor() {
+	m := champ.New[string, int]()
+	for i, key := range []string{"apple", "banana", "cherry", "date", "elderberry"} {
+		m = m.Set(key, i)
+	}
+
+	// Page through the map two entries at a time, resuming from the token of the previous page
+	token := m.Cursor().Token()
+	for page := 1; ; page++ {
+		c, err := m.Seek(token)
+		if err != nil {
+			panic(err)
+		}
+
+		count := 0
+		for range 2 {
+			if _, _, ok := c.Next(); ok {
+				count++
+			}
+		}
+		if count == 0 {
+			break
+		}
+		fmt.Printf("Page %d: %d entries\n", page, count)
+		token = c.Token()
+	}
+
+	// Output:
+	// Page 1: 2 entries
+	// Page 2: 2 entries
+	// Page 3: 1 entries
+}
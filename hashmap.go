@@ -0,0 +1,319 @@
+package champ
+
+import (
+	"iter"
+	"slices"
+)
+
+// Hashable is implemented by keys which cannot be compared with ==,
+// such as slices or structs containing slices.
+//
+// Equal keys must return equal hashes.
+type Hashable[K any] interface {
+	Hash() uint64
+	Equal(other K) bool
+}
+
+// HashMap represents a CHAMP map for keys implementing Hashable.
+//
+// Entries are stored in the same nodes as Map,
+// placed by the Hash of their keys and compared with Equal instead of ==.
+// The zero value is an empty map ready to use.
+type HashMap[K Hashable[K], V any] struct {
+	root node[K, V]
+	size int
+}
+
+// hashHashable returns the hash of a HashMap key.
+func hashHashable[K Hashable[K]](key K) uint64 {
+	return key.Hash()
+}
+
+// NewHashMap creates a new empty HashMap.
+func NewHashMap[K Hashable[K], V any]() *HashMap[K, V] {
+	return &HashMap[K, V]{}
+}
+
+// Get retrieves a value by key.
+func (m *HashMap[K, V]) Get(key K) (V, bool) {
+	if m.root == nil {
+		var zero V
+		return zero, false
+	}
+	return hashNodeGet(m.root, key, key.Hash(), 0)
+}
+
+// Set sets or updates a key-value pair.
+func (m *HashMap[K, V]) Set(key K, value V) *HashMap[K, V] {
+	h := key.Hash()
+	if m.root == nil {
+		return &HashMap[K, V]{
+			root: &bitmapIndexedNode[K, V]{
+				datamap: uint32(1 << (h & bitMask)),
+				keys:    []K{key},
+				values:  []V{value},
+			},
+			size: 1,
+		}
+	}
+
+	root, added := hashNodeSet(m.root, key, value, h, 0)
+	size := m.size
+	if added {
+		size++
+	}
+	return &HashMap[K, V]{root: root, size: size}
+}
+
+// Delete removes a key from the map.
+func (m *HashMap[K, V]) Delete(key K) *HashMap[K, V] {
+	if m.root == nil {
+		return m
+	}
+
+	root, deleted := hashNodeDel(m.root, key, key.Hash(), 0)
+	if !deleted {
+		return m
+	}
+	return &HashMap[K, V]{root: root, size: m.size - 1}
+}
+
+// Update sets, replaces or deletes the entry of key in a single descent through the trie,
+// like Map.Update.
+func (m *HashMap[K, V]) Update(key K, fn func(old V, ok bool) (V, bool)) *HashMap[K, V] {
+	return m.UpdateFunc(key, func(old V, ok bool) (V, UpdateAction) {
+		value, keep := fn(old, ok)
+		if keep {
+			return value, UpdateSet
+		}
+		return value, UpdateDelete
+	})
+}
+
+// UpdateFunc is like Update but fn returns what to do with the entry, like Map.UpdateFunc.
+// m itself is returned if fn returns UpdateKeep, or UpdateDelete for an absent key.
+func (m *HashMap[K, V]) UpdateFunc(key K, fn func(old V, ok bool) (V, UpdateAction)) *HashMap[K, V] {
+	h := key.Hash()
+
+	if m.root == nil {
+		var zero V
+		value, action := fn(zero, false)
+		if action != UpdateSet {
+			return m
+		}
+		return &HashMap[K, V]{
+			root: &bitmapIndexedNode[K, V]{
+				datamap: uint32(1 << (h & bitMask)),
+				keys:    []K{key},
+				values:  []V{value},
+			},
+			size: 1,
+		}
+	}
+
+	root, delta := hashNodeUpdate(m.root, key, h, 0, fn)
+	if root == m.root {
+		return m
+	}
+	return &HashMap[K, V]{root: root, size: m.size + delta}
+}
+
+// Len returns the number of entries
+func (m *HashMap[K, V]) Len() int {
+	return m.size
+}
+
+// All returns an iterator over key-value pairs.
+func (m *HashMap[K, V]) All() iter.Seq2[K, V] {
+	return allNode(m.root)
+}
+
+// Keys returns an iterator over the keys.
+func (m *HashMap[K, V]) Keys() iter.Seq[K] {
+	return keysNode(m.root)
+}
+
+// Values returns an iterator over the values.
+func (m *HashMap[K, V]) Values() iter.Seq[V] {
+	return valuesNode(m.root)
+}
+
+// hashNodeGet is nodeGet comparing keys with Equal.
+func hashNodeGet[K Hashable[K], V any](n node[K, V], key K, hash uint64, shift uint) (V, bool) {
+	var zero V
+	for {
+		switch nn := n.(type) {
+		case *bitmapIndexedNode[K, V]:
+			bit := uint32(1 << ((hash >> shift) & bitMask))
+			if nn.datamap&bit != 0 {
+				idx := popcount(nn.datamap & (bit - 1))
+				if nn.keys[idx].Equal(key) {
+					return nn.values[idx], true
+				}
+				return zero, false
+			}
+			if nn.nodemap&bit == 0 {
+				return zero, false
+			}
+			n = nn.nodes[popcount(nn.nodemap&(bit-1))]
+			shift += bitsPerLevel
+		case *collisionNode[K, V]:
+			if i := indexHashable(nn.keys, key); i >= 0 {
+				return nn.values[i], true
+			}
+			return zero, false
+		default:
+			return zero, false
+		}
+	}
+}
+
+// hashNodeSet is nodeSet comparing keys with Equal.
+func hashNodeSet[K Hashable[K], V any](n node[K, V], key K, value V, hash uint64, shift uint) (node[K, V], bool) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		bit := uint32(1 << ((hash >> shift) & bitMask))
+
+		if n.datamap&bit != 0 {
+			idx := popcount(n.datamap & (bit - 1))
+			if n.keys[idx].Equal(key) {
+				return n.withValue(idx, value), false
+			}
+			return n.withSubNode(bit, key, value, hash, shift, hashHashable[K]), true
+		}
+
+		if n.nodemap&bit != 0 {
+			child := n.nodes[popcount(n.nodemap&(bit-1))]
+			newChild, added := hashNodeSet(child, key, value, hash, shift+bitsPerLevel)
+			return n.withChild(bit, newChild), added
+		}
+
+		return n.withData(bit, key, value), true
+	case *collisionNode[K, V]:
+		if i := indexHashable(n.keys, key); i >= 0 {
+			return n.withValue(i, value), false
+		}
+		return n.withEntry(key, value), true
+	}
+	return n, false
+}
+
+// hashNodeDel is nodeDel comparing keys with Equal.
+func hashNodeDel[K Hashable[K], V any](n node[K, V], key K, hash uint64, shift uint) (node[K, V], bool) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		bit := uint32(1 << ((hash >> shift) & bitMask))
+
+		if n.datamap&bit != 0 {
+			if !n.keys[popcount(n.datamap&(bit-1))].Equal(key) {
+				return n, false
+			}
+			return n.withoutData(bit), true
+		}
+
+		if n.nodemap&bit != 0 {
+			newChild, deleted := hashNodeDel(n.nodes[popcount(n.nodemap&(bit-1))], key, hash, shift+bitsPerLevel)
+			if !deleted {
+				return n, false
+			}
+			return n.withChild(bit, newChild), true
+		}
+
+		return n, false
+	case *collisionNode[K, V]:
+		if i := indexHashable(n.keys, key); i >= 0 {
+			return n.without(i), true
+		}
+		return n, false
+	}
+	return n, false
+}
+
+// hashNodeUpdate is nodeUpdate comparing keys with Equal.
+func hashNodeUpdate[K Hashable[K], V any](n node[K, V], key K, hash uint64, shift uint, fn updateFunc[V]) (node[K, V], int) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		bit := uint32(1 << ((hash >> shift) & bitMask))
+
+		if n.nodemap&bit != 0 {
+			child := n.nodes[popcount(n.nodemap&(bit-1))]
+			newChild, delta := hashNodeUpdate(child, key, hash, shift+bitsPerLevel, fn)
+			if newChild == child {
+				return n, 0
+			}
+			return n.withChild(bit, newChild), delta
+		}
+
+		idx := -1
+		if n.datamap&bit != 0 {
+			if i := popcount(n.datamap & (bit - 1)); n.keys[i].Equal(key) {
+				idx = i
+			}
+		}
+		return n.updateData(bit, idx, key, hash, shift, fn, hashHashable[K])
+	case *collisionNode[K, V]:
+		return n.updateEntry(indexHashable(n.keys, key), key, fn)
+	}
+	return n, 0
+}
+
+// indexHashable returns the index of the first key Equal to key, or -1 if there is none.
+func indexHashable[K Hashable[K]](keys []K, key K) int {
+	for i, k := range keys {
+		if k.Equal(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// EqualHashMaps checks if two HashMaps contain the same key-value pairs.
+//
+// The tries are compared structurally, skipping subtrees shared by both maps.
+func EqualHashMaps[K Hashable[K], V comparable](m1, m2 *HashMap[K, V]) bool {
+	if m1.size != m2.size {
+		return false
+	}
+	return equalHashNode(m1.root, m2.root)
+}
+
+func equalHashNode[K Hashable[K], V comparable](n1, n2 node[K, V]) bool {
+	// short-circuit for identical pointers
+	if n1 == n2 {
+		return true
+	}
+
+	switch n1 := n1.(type) {
+	case *bitmapIndexedNode[K, V]:
+		n2, ok := n2.(*bitmapIndexedNode[K, V])
+		if !ok || n1.datamap != n2.datamap || n1.nodemap != n2.nodemap {
+			return false
+		}
+		for i := range n1.keys {
+			if !n1.keys[i].Equal(n2.keys[i]) || n1.values[i] != n2.values[i] {
+				return false
+			}
+		}
+		for i := range n1.nodes {
+			if !equalHashNode(n1.nodes[i], n2.nodes[i]) {
+				return false
+			}
+		}
+		return true
+	case *collisionNode[K, V]:
+		n2, ok := n2.(*collisionNode[K, V])
+		if !ok || len(n1.keys) != len(n2.keys) {
+			return false
+		}
+		// the entries of collision nodes are compared regardless of their order
+		for i, k := range n1.keys {
+			j := slices.IndexFunc(n2.keys, k.Equal)
+			if j < 0 || n1.values[i] != n2.values[j] {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
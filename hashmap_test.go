@@ -0,0 +1,223 @@
+package champ
+
+import (
+	"bytes"
+	"fmt"
+	"hash/maphash"
+	"testing"
+)
+
+// bytesKey is a non-comparable key type for testing.
+type bytesKey []byte
+
+func (k bytesKey) Hash() uint64 {
+	return maphash.Bytes(seed, k)
+}
+
+func (k bytesKey) Equal(other bytesKey) bool {
+	return bytes.Equal(k, other)
+}
+
+// collidingKey is a non-comparable key type whose hashes always collide.
+type collidingKey []byte
+
+func (k collidingKey) Hash() uint64 {
+	return 42
+}
+
+func (k collidingKey) Equal(other collidingKey) bool {
+	return bytes.Equal(k, other)
+}
+
+func TestHashMap(t *testing.T) {
+	t.Run("basic operations", func(t *testing.T) {
+		var m HashMap[bytesKey, int]
+
+		m1 := m.Set(bytesKey("key1"), 10).Set(bytesKey("key2"), 20).Set(bytesKey("key1"), 100)
+		if m1.Len() != 2 {
+			t.Fatalf("Len() expected %d, actual %d", 2, m1.Len())
+		}
+		if v, ok := m1.Get(bytesKey("key1")); !ok || v != 100 {
+			t.Errorf("Get(%q) expected (%d, true), actual (%d, %v)", "key1", 100, v, ok)
+		}
+
+		m2 := m1.Delete(bytesKey("key1"))
+		if m2.Len() != 1 {
+			t.Fatalf("Len() expected %d, actual %d", 1, m2.Len())
+		}
+		if _, ok := m2.Get(bytesKey("key1")); ok {
+			t.Errorf("Get(%q) expected ok=false after Delete", "key1")
+		}
+		if _, ok := m1.Get(bytesKey("key1")); !ok {
+			t.Errorf("Get(%q) expected ok=true on original map", "key1")
+		}
+		if m3 := m2.Delete(bytesKey("missing")); m3 != m2 {
+			t.Error("Delete() of a missing key returned a new map")
+		}
+	})
+
+	t.Run("colliding keys", func(t *testing.T) {
+		const n = 100
+
+		m := NewHashMap[collidingKey, int]()
+		for i := range n {
+			m = m.Set(collidingKey(fmt.Sprintf("key%d", i)), i)
+		}
+		if m.Len() != n {
+			t.Fatalf("Len() expected %d, actual %d", n, m.Len())
+		}
+		for i := range n {
+			if v, ok := m.Get(collidingKey(fmt.Sprintf("key%d", i))); !ok || v != i {
+				t.Errorf("Get(%q) expected (%d, true), actual (%d, %v)", fmt.Sprintf("key%d", i), i, v, ok)
+			}
+		}
+
+		for i := 0; i < n; i += 2 {
+			m = m.Delete(collidingKey(fmt.Sprintf("key%d", i)))
+		}
+		if m.Len() != n/2 {
+			t.Fatalf("Len() expected %d, actual %d", n/2, m.Len())
+		}
+		count := 0
+		for k, v := range m.All() {
+			if string(k) != fmt.Sprintf("key%d", v) || v%2 == 0 {
+				t.Errorf("All() yielded unexpected entry %q => %d", k, v)
+			}
+			count++
+		}
+		if count != n/2 {
+			t.Errorf("All() yielded %d entries, expected %d", count, n/2)
+		}
+	})
+}
+
+func TestHashMapNodes(t *testing.T) {
+	t.Run("colliding keys share a collision node", func(t *testing.T) {
+		m := NewHashMap[collidingKey, int]()
+		for i := range 3 {
+			m = m.Set(collidingKey(fmt.Sprintf("key%d", i)), i)
+		}
+
+		// descend along the hash of the keys down to the collision node
+		n := m.root
+		for range maxDepth {
+			b, ok := n.(*bitmapIndexedNode[collidingKey, int])
+			if !ok || len(b.keys) != 0 || len(b.nodes) != 1 {
+				t.Fatalf("expected a path of single child nodes, actual %v", n)
+			}
+			n = b.nodes[0]
+		}
+		c, ok := n.(*collisionNode[collidingKey, int])
+		if !ok {
+			t.Fatalf("expected a collision node, actual %T", n)
+		}
+		if len(c.keys) != 3 {
+			t.Errorf("collision node expected %d keys, actual %d", 3, len(c.keys))
+		}
+	})
+
+	t.Run("single key is inlined after Delete", func(t *testing.T) {
+		m := NewHashMap[collidingKey, int]().
+			Set(collidingKey("a"), 1).
+			Set(collidingKey("b"), 2).
+			Delete(collidingKey("a"))
+
+		root, ok := m.root.(*bitmapIndexedNode[collidingKey, int])
+		if !ok || len(root.nodes) != 0 || len(root.keys) != 1 {
+			t.Fatalf("expected a root holding a single entry, actual %v", m.root)
+		}
+		if v, ok := m.Get(collidingKey("b")); !ok || v != 2 {
+			t.Errorf("Get(%q) expected (%d, true), actual (%d, %v)", "b", 2, v, ok)
+		}
+		if m = m.Delete(collidingKey("b")); m.root != nil || m.Len() != 0 {
+			t.Errorf("expected an empty map, actual %d entries", m.Len())
+		}
+	})
+}
+
+func TestHashMapUpdate(t *testing.T) {
+	keys := []string{"a", "b", "c"}
+	increment := func(old int, ok bool) (int, bool) {
+		return old + 1, true
+	}
+
+	m := NewHashMap[collidingKey, int]()
+	for range 2 {
+		for _, k := range keys {
+			m = m.Update(collidingKey(k), increment)
+		}
+	}
+
+	t.Run("set and replace", func(t *testing.T) {
+		if m.Len() != len(keys) {
+			t.Fatalf("Len() expected %d, actual %d", len(keys), m.Len())
+		}
+		for _, k := range keys {
+			if v, ok := m.Get(collidingKey(k)); !ok || v != 2 {
+				t.Errorf("Get(%q) expected (%d, true), actual (%d, %v)", k, 2, v, ok)
+			}
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		actual := m.Update(collidingKey("a"), func(int, bool) (int, bool) { return 0, false })
+		if _, ok := actual.Get(collidingKey("a")); ok {
+			t.Errorf("Get(%q) expected ok=false after Update", "a")
+		}
+		if actual.Len() != len(keys)-1 {
+			t.Errorf("Len() expected %d, actual %d", len(keys)-1, actual.Len())
+		}
+	})
+
+	t.Run("keep", func(t *testing.T) {
+		keep := func(old int, ok bool) (int, UpdateAction) { return old, UpdateKeep }
+		if actual := m.UpdateFunc(collidingKey("a"), keep); actual != m {
+			t.Error("UpdateFunc() returning UpdateKeep expected the map itself")
+		}
+		if actual := m.UpdateFunc(collidingKey("missing"), keep); actual != m {
+			t.Error("UpdateFunc() returning UpdateKeep for an absent key expected the map itself")
+		}
+	})
+}
+
+func TestEqualHashMaps(t *testing.T) {
+	m1 := NewHashMap[bytesKey, int]()
+	m2 := NewHashMap[bytesKey, int]()
+	for i := range 1000 {
+		m1 = m1.Set(bytesKey(fmt.Sprintf("key%d", i)), i)
+	}
+	for i := 999; i >= 0; i-- {
+		m2 = m2.Set(bytesKey(fmt.Sprintf("key%d", i)), i)
+	}
+
+	if !EqualHashMaps(m1, m2) {
+		t.Error("EqualHashMaps() = false for maps with the same entries")
+	}
+	if EqualHashMaps(m1, m2.Set(bytesKey("key0"), -1)) {
+		t.Error("EqualHashMaps() = true for maps with different values")
+	}
+	if EqualHashMaps(m1, m2.Delete(bytesKey("key0"))) {
+		t.Error("EqualHashMaps() = true for maps with different sizes")
+	}
+}
+
+func TestEqualHashMapsCollisionOrder(t *testing.T) {
+	m1 := NewHashMap[collidingKey, int]()
+	m2 := NewHashMap[collidingKey, int]()
+	for i := range 20 {
+		m1 = m1.Set(collidingKey(fmt.Sprintf("key%d", i)), i)
+	}
+	for i := 19; i >= 0; i-- {
+		m2 = m2.Set(collidingKey(fmt.Sprintf("key%d", i)), i)
+	}
+
+	if !EqualHashMaps(m1, m2) {
+		t.Error("EqualHashMaps() = false for collision nodes with entries in different order")
+	}
+	if EqualHashMaps(m1, m2.Set(collidingKey("key0"), -1)) {
+		t.Error("EqualHashMaps() = true for collision nodes with different values")
+	}
+	if !EqualHashMaps(&HashMap[collidingKey, int]{}, NewHashMap[collidingKey, int]()) {
+		t.Error("EqualHashMaps() = false for empty maps")
+	}
+}
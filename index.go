@@ -0,0 +1,217 @@
+package champ
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+)
+
+// Index is a read-only view of a map addressing its entries by position in iteration order.
+//
+// The nodes of a map do not record the sizes of their subtrees, so maps that are never indexed pay nothing for it.
+// Instead, Index mirrors the trie with the offsets of the subtrees.
+// Building it walks every node once in O(n), while Reindex follows a changing map
+// by rebuilding only the nodes of the new version which are not shared with the indexed one.
+// The index stays valid as long as it is used, since the map never changes.
+type Index[K comparable, V any] struct {
+	m    *Map[K, V]
+	root *indexNode[K, V]
+}
+
+// indexNode mirrors a node of the trie with the offsets of its entries and children.
+type indexNode[K comparable, V any] struct {
+	keys   []K
+	values []V
+	// offsets[i] is the position of the first entry of children[i] relative to the node,
+	// and the last offset is the number of entries in the subtree.
+	offsets  []int
+	children []*indexNode[K, V]
+}
+
+// Index returns an index of the entries of the map by their position in iteration order.
+// Building the index walks every node once, which costs O(n).
+func (m *Map[K, V]) Index() *Index[K, V] {
+	x := &Index[K, V]{m: m}
+	if m.root != nil {
+		x.root = newIndexNode(m.root, nil, nil)
+	}
+	return x
+}
+
+// Reindex returns an index of m, reusing the parts of x which mirror subtrees shared by m and the map of x.
+//
+// Only the nodes of m which are not in the map of x are visited,
+// so indexing a version derived from the map of x by k edits costs O(k log n) instead of O(n).
+// Any map may be passed, at worst it shares no node and is indexed from scratch.
+func (x *Index[K, V]) Reindex(m *Map[K, V]) *Index[K, V] {
+	y := &Index[K, V]{m: m}
+	if m.root != nil {
+		y.root = newIndexNode(m.root, x.m.root, x.root)
+	}
+	return y
+}
+
+// newIndexNode returns the index of the node n.
+// prev is the node at the same position in a previously indexed trie, and xprev its index, both nil if there is none.
+// The indexes of subtrees shared with prev are reused.
+func newIndexNode[K comparable, V any](n, prev node[K, V], xprev *indexNode[K, V]) *indexNode[K, V] {
+	if n == prev {
+		return xprev
+	}
+
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		x := &indexNode[K, V]{
+			keys:     n.keys,
+			values:   n.values,
+			offsets:  make([]int, len(n.nodes)+1),
+			children: make([]*indexNode[K, V], len(n.nodes)),
+		}
+		p, _ := prev.(*bitmapIndexedNode[K, V])
+		x.offsets[0] = len(n.keys)
+		i := 0
+		for bits := n.nodemap; bits != 0; bits &= bits - 1 {
+			// the child at the same position in prev, which is shared if the child is unchanged
+			var prevChild node[K, V]
+			var xprevChild *indexNode[K, V]
+			if bit := bits & -bits; p != nil && p.nodemap&bit != 0 {
+				j := popcount(p.nodemap & (bit - 1))
+				prevChild, xprevChild = p.nodes[j], xprev.children[j]
+			}
+
+			x.children[i] = newIndexNode(n.nodes[i], prevChild, xprevChild)
+			x.offsets[i+1] = x.offsets[i] + x.children[i].size()
+			i++
+		}
+		return x
+	case *collisionNode[K, V]:
+		return &indexNode[K, V]{keys: n.keys, values: n.values, offsets: []int{len(n.keys)}}
+	}
+	return nil
+}
+
+// size returns the number of entries in the subtree.
+func (x *indexNode[K, V]) size() int {
+	return x.offsets[len(x.offsets)-1]
+}
+
+// Len returns the number of entries.
+func (x *Index[K, V]) Len() int {
+	return x.m.size
+}
+
+// At returns the entry at position i in iteration order in O(log n).
+// It panics if i is out of range.
+func (x *Index[K, V]) At(i int) (K, V) {
+	if i < 0 || i >= x.m.size {
+		panic("champ: index out of range")
+	}
+
+	n := x.root
+	for i >= len(n.keys) {
+		// the child holding i is the last one starting at or before i
+		c, found := slices.BinarySearch(n.offsets, i)
+		if !found {
+			c--
+		}
+		i -= n.offsets[c]
+		n = n.children[c]
+	}
+	return n.keys[i], n.values[i]
+}
+
+// IndexOf returns the position of key in iteration order in O(log n), or -1 if key is not present.
+func (x *Index[K, V]) IndexOf(key K) int {
+	if x.root == nil {
+		return -1
+	}
+
+	hash := x.m.hasher.hash(key)
+	index := 0
+	n, xn := x.m.root, x.root
+	for shift := uint(0); ; shift += bitsPerLevel {
+		switch nn := n.(type) {
+		case *bitmapIndexedNode[K, V]:
+			bit := uint32(1 << ((hash >> shift) & bitMask))
+			if nn.datamap&bit != 0 {
+				idx := popcount(nn.datamap & (bit - 1))
+				if nn.keys[idx] != key {
+					return -1
+				}
+				return index + idx
+			}
+			if nn.nodemap&bit == 0 {
+				return -1
+			}
+
+			idx := popcount(nn.nodemap & (bit - 1))
+			index += xn.offsets[idx]
+			n, xn = nn.nodes[idx], xn.children[idx]
+		case *collisionNode[K, V]:
+			if i := slices.Index(nn.keys, key); i >= 0 {
+				return index + i
+			}
+			return -1
+		}
+	}
+}
+
+// Sample returns an entry chosen uniformly at random using r in O(log n), and false if the map is empty.
+// If r is nil, the global random source of math/rand/v2 is used.
+func (x *Index[K, V]) Sample(r *rand.Rand) (K, V, bool) {
+	if x.m.size == 0 {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+
+	var i int
+	if r == nil {
+		i = rand.IntN(x.m.size)
+	} else {
+		i = r.IntN(x.m.size)
+	}
+	k, v := x.At(i)
+	return k, v, true
+}
+
+// Slice returns an iterator over the entries at positions [i, j) in iteration order.
+// It panics if the positions are out of range.
+//
+// Subtrees before i are skipped by their offsets, so the iteration costs O(log n + j - i).
+func (x *Index[K, V]) Slice(i, j int) iter.Seq2[K, V] {
+	if i < 0 || j > x.m.size || i > j {
+		panic("champ: slice bounds out of range")
+	}
+
+	return func(yield func(K, V) bool) {
+		if i == j {
+			return
+		}
+		remaining := j - i
+		sliceNode(x.root, i, &remaining, yield)
+	}
+}
+
+// sliceNode yields the entries of n starting at position skip, until remaining reaches zero.
+// It returns false if the iteration should stop.
+func sliceNode[K comparable, V any](n *indexNode[K, V], skip int, remaining *int, yield func(K, V) bool) bool {
+	for idx := skip; idx < len(n.keys); idx++ {
+		if !yield(n.keys[idx], n.values[idx]) {
+			return false
+		}
+		if *remaining--; *remaining == 0 {
+			return false
+		}
+	}
+
+	for c, child := range n.children {
+		if skip >= n.offsets[c+1] {
+			continue
+		}
+		if !sliceNode(child, max(skip-n.offsets[c], 0), remaining, yield) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+package champ
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	// low entropy hash function producing deep tries and collision nodes
+	lowEntropy := func(key string) uint64 { return uint64(len(key)+int(key[len(key)-1])%3) << 58 }
+
+	for _, tt := range []struct {
+		name string
+		m    func() *Map[string, int]
+	}{
+		{
+			name: "empty",
+			m:    New[string, int],
+		},
+		{
+			name: "small map",
+			m: func() *Map[string, int] {
+				return New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+			},
+		},
+		{
+			name: "large map",
+			m: func() *Map[string, int] {
+				m := New[string, int]()
+				for i := range 5000 {
+					m = m.Set(fmt.Sprintf("k%d", i), i)
+				}
+				return m
+			},
+		},
+		{
+			name: "low entropy hash function",
+			m: func() *Map[string, int] {
+				m := NewWithHasher[string, int](lowEntropy)
+				for i := range 500 {
+					m = m.Set(fmt.Sprintf("k%d", i), i)
+				}
+				return m
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.m()
+			x := m.Index()
+			keys := slices.Collect(m.Keys())
+
+			if x.Len() != m.Len() {
+				t.Errorf("Len() expected %d, actual %d", m.Len(), x.Len())
+			}
+			for i, key := range keys {
+				k, v := x.At(i)
+				if k != key {
+					t.Errorf("At(%d) expected key %q, actual %q", i, key, k)
+				}
+				if expected, _ := m.Get(key); v != expected {
+					t.Errorf("At(%d) expected value %d, actual %d", i, expected, v)
+				}
+				if actual := x.IndexOf(key); actual != i {
+					t.Errorf("IndexOf(%q) expected %d, actual %d", key, i, actual)
+				}
+			}
+			if actual := x.IndexOf("missing"); actual != -1 {
+				t.Errorf("IndexOf(%q) expected -1, actual %d", "missing", actual)
+			}
+
+			for _, r := range [][2]int{{0, 0}, {0, len(keys)}, {len(keys) / 3, len(keys) / 2}, {len(keys), len(keys)}} {
+				var actual []string
+				for k := range x.Slice(r[0], r[1]) {
+					actual = append(actual, k)
+				}
+				if expected := keys[r[0]:r[1]]; !slices.Equal(actual, expected) {
+					t.Errorf("Slice(%d, %d) expected %v, actual %v", r[0], r[1], expected, actual)
+				}
+			}
+		})
+	}
+
+	t.Run("early break", func(t *testing.T) {
+		m := New[int, int]()
+		for i := range 1000 {
+			m = m.Set(i, i)
+		}
+
+		count := 0
+		for range m.Index().Slice(100, 900) {
+			if count++; count == 10 {
+				break
+			}
+		}
+		if count != 10 {
+			t.Errorf("Slice() yielded %d entries after break, expected %d", count, 10)
+		}
+	})
+
+	t.Run("sample", func(t *testing.T) {
+		if _, _, ok := New[int, int]().Index().Sample(nil); ok {
+			t.Error("Sample() of an empty map expected false")
+		}
+
+		const size, samples = 10, 10000
+		m := New[int, int]()
+		for i := range size {
+			m = m.Set(i, i)
+		}
+
+		x := m.Index()
+		r := rand.New(rand.NewPCG(1, 2))
+		counts := make([]int, size)
+		for range samples {
+			k, v, ok := x.Sample(r)
+			if !ok || k != v {
+				t.Fatalf("Sample() returned (%d, %d, %v)", k, v, ok)
+			}
+			counts[k]++
+		}
+		for k, c := range counts {
+			if c < samples/size/2 || c > samples/size*2 {
+				t.Errorf("Sample() returned key %d %d times out of %d", k, c, samples)
+			}
+		}
+	})
+
+	t.Run("reindex", func(t *testing.T) {
+		for _, base := range []*Map[string, int]{New[string, int](), NewWithHasher[string, int](lowEntropy)} {
+			m := base
+			for i := range 1000 {
+				m = m.Set(fmt.Sprintf("k%d", i), i)
+			}
+
+			x := m.Index()
+			r := rand.New(rand.NewPCG(1, 2))
+			for range 200 {
+				key := fmt.Sprintf("k%d", r.IntN(1500))
+				switch b := m.Transient(); r.IntN(3) {
+				case 0:
+					m = m.Set(key, -1)
+				case 1:
+					m = m.Delete(key)
+				default:
+					b.Set(key, -2)
+					b.Delete(fmt.Sprintf("k%d", r.IntN(1500)))
+					m = b.Persistent()
+				}
+
+				x = x.Reindex(m)
+				expected := m.Index()
+				if x.Len() != expected.Len() {
+					t.Fatalf("Reindex() Len() expected %d, actual %d", expected.Len(), x.Len())
+				}
+				for i := range m.Len() {
+					k, v := x.At(i)
+					if ek, ev := expected.At(i); k != ek || v != ev {
+						t.Fatalf("Reindex() At(%d) expected (%q, %d), actual (%q, %d)", i, ek, ev, k, v)
+					}
+					if actual := x.IndexOf(k); actual != i {
+						t.Fatalf("Reindex() IndexOf(%q) expected %d, actual %d", k, i, actual)
+					}
+				}
+			}
+
+			if actual := x.Reindex(base); actual.Len() != 0 || actual.IndexOf("k1") != -1 {
+				t.Error("Reindex() of an empty map expected an empty index")
+			}
+		}
+	})
+
+	t.Run("reindex shares unchanged subtrees", func(t *testing.T) {
+		m := New[int, int]()
+		for i := range 5000 {
+			m = m.Set(i, i)
+		}
+		x := m.Index()
+
+		if actual := x.Reindex(m); actual.root != x.root {
+			t.Error("Reindex() of the same map rebuilt the root")
+		}
+
+		y := x.Reindex(m.Set(-1, -1))
+		shared := 0
+		for _, child := range y.root.children {
+			if slices.Contains(x.root.children, child) {
+				shared++
+			}
+		}
+		if expected := len(y.root.children) - 1; shared < expected {
+			t.Errorf("Reindex() after a single edit expected at least %d shared children, actual %d", expected, shared)
+		}
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		x := New[int, int]().Set(1, 1).Index()
+		for _, tt := range []struct {
+			name string
+			fn   func()
+		}{
+			{"At(-1)", func() { x.At(-1) }},
+			{"At(Len())", func() { x.At(1) }},
+			{"Slice(0, 2)", func() { x.Slice(0, 2) }},
+			{"Slice(1, 0)", func() { x.Slice(1, 0) }},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				defer func() {
+					if recover() == nil {
+						t.Errorf("%s expected panic", tt.name)
+					}
+				}()
+				tt.fn()
+			})
+		}
+	})
+}
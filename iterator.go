@@ -0,0 +1,115 @@
+package champ
+
+import "iter"
+
+// Iterator is a pull iterator over the entries of a map in the same order as All.
+//
+// It walks the trie with an explicit stack of fixed depth,
+// so it neither allocates per node nor needs a goroutine like iter.Pull.
+type Iterator[K any, V any] struct {
+	stack [maxDepth + 1]iteratorFrame[K, V]
+	depth int
+}
+
+// iteratorFrame is a node on the path of an iterator along with the entries and children left to visit.
+type iteratorFrame[K any, V any] struct {
+	keys   []K
+	values []V
+	nodes  []node[K, V]
+	next   int // index of the next entry
+	child  int // index of the next child
+}
+
+// Iterator returns an iterator positioned before the first entry of the map.
+func (m *Map[K, V]) Iterator() *Iterator[K, V] {
+	it := &Iterator[K, V]{}
+	it.reset(m.root)
+	return it
+}
+
+// Next returns the next entry and advances the iterator, or returns false if there are no more entries.
+func (it *Iterator[K, V]) Next() (K, V, bool) {
+	for it.depth > 0 {
+		f := &it.stack[it.depth-1]
+		if f.next < len(f.keys) {
+			i := f.next
+			f.next++
+			return f.keys[i], f.values[i], true
+		}
+		if f.child < len(f.nodes) {
+			child := f.nodes[f.child]
+			f.child++
+			it.push(child)
+			continue
+		}
+		// drop the references to the node so that it can be collected
+		*f = iteratorFrame[K, V]{}
+		it.depth--
+	}
+
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// reset positions the iterator before the first entry of the subtree n, which may be nil.
+func (it *Iterator[K, V]) reset(n node[K, V]) {
+	clear(it.stack[:it.depth])
+	it.depth = 0
+	if n != nil {
+		it.push(n)
+	}
+}
+
+func (it *Iterator[K, V]) push(n node[K, V]) {
+	keys, values := n.entries()
+	f := iteratorFrame[K, V]{keys: keys, values: values}
+	if n, ok := n.(*bitmapIndexedNode[K, V]); ok {
+		f.nodes = n.nodes
+	}
+	it.stack[it.depth] = f
+	it.depth++
+}
+
+// allNode returns an iterator over the entries of the subtree n.
+// The iterator lives on the stack of the range loop, so iterating does not allocate.
+func allNode[K any, V any](n node[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		var it Iterator[K, V]
+		it.reset(n)
+		for {
+			k, v, ok := it.Next()
+			if !ok || !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// keysNode returns an iterator over the keys of the subtree n.
+func keysNode[K any, V any](n node[K, V]) iter.Seq[K] {
+	return func(yield func(K) bool) {
+		var it Iterator[K, V]
+		it.reset(n)
+		for {
+			k, _, ok := it.Next()
+			if !ok || !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// valuesNode returns an iterator over the values of the subtree n.
+func valuesNode[K any, V any](n node[K, V]) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		var it Iterator[K, V]
+		it.reset(n)
+		for {
+			_, v, ok := it.Next()
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
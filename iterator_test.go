@@ -0,0 +1,126 @@
+package champ
+
+import (
+	"fmt"
+	"testing"
+)
+
+// walkNode returns the entries of n in iteration order by recursion.
+func walkNode[K comparable, V any](n node[K, V]) ([]K, []V) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		keys, values := append([]K(nil), n.keys...), append([]V(nil), n.values...)
+		for _, child := range n.nodes {
+			k, v := walkNode(child)
+			keys, values = append(keys, k...), append(values, v...)
+		}
+		return keys, values
+	case *collisionNode[K, V]:
+		return n.keys, n.values
+	}
+	return nil, nil
+}
+
+func TestIterator(t *testing.T) {
+	// low entropy hash function producing deep tries and collision nodes
+	lowEntropy := func(key string) uint64 { return uint64(len(key)+int(key[len(key)-1])%3) << 58 }
+
+	for _, tt := range []struct {
+		name string
+		m    func() *Map[string, int]
+	}{
+		{
+			name: "empty",
+			m:    New[string, int],
+		},
+		{
+			name: "small map",
+			m: func() *Map[string, int] {
+				return New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+			},
+		},
+		{
+			name: "large map",
+			m: func() *Map[string, int] {
+				m := New[string, int]()
+				for i := range 5000 {
+					m = m.Set(fmt.Sprintf("k%d", i), i)
+				}
+				return m
+			},
+		},
+		{
+			name: "low entropy hash function",
+			m: func() *Map[string, int] {
+				m := NewWithHasher[string, int](lowEntropy)
+				for i := range 500 {
+					m = m.Set(fmt.Sprintf("k%d", i), i)
+				}
+				return m
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.m()
+			keys, values := walkNode(m.root)
+
+			it := m.Iterator()
+			for i := range keys {
+				k, v, ok := it.Next()
+				if !ok {
+					t.Fatalf("Next() returned false at %d, expected %d entries", i, len(keys))
+				}
+				if k != keys[i] || v != values[i] {
+					t.Fatalf("Next() at %d expected (%q, %d), actual (%q, %d)", i, keys[i], values[i], k, v)
+				}
+			}
+			for range 2 {
+				if _, _, ok := it.Next(); ok {
+					t.Error("Next() of an exhausted iterator expected false")
+				}
+			}
+
+			i := 0
+			for k, v := range m.All() {
+				if k != keys[i] || v != values[i] {
+					t.Fatalf("All() at %d expected (%q, %d), actual (%q, %d)", i, keys[i], values[i], k, v)
+				}
+				i++
+			}
+			i = 0
+			for k := range m.Keys() {
+				if k != keys[i] {
+					t.Fatalf("Keys() at %d expected %q, actual %q", i, keys[i], k)
+				}
+				i++
+			}
+			i = 0
+			for v := range m.Values() {
+				if v != values[i] {
+					t.Fatalf("Values() at %d expected %d, actual %d", i, values[i], v)
+				}
+				i++
+			}
+			if i != len(values) {
+				t.Errorf("Values() yielded %d values, expected %d", i, len(values))
+			}
+		})
+	}
+
+	t.Run("no allocations", func(t *testing.T) {
+		m := New[int, int]()
+		for i := range 10000 {
+			m = m.Set(i, i)
+		}
+		seq := m.All()
+
+		allocs := testing.AllocsPerRun(10, func() {
+			for k, v := range seq {
+				_, _ = k, v
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("All() expected no allocations, actual %v", allocs)
+		}
+	})
+}
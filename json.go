@@ -0,0 +1,185 @@
+package champ
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+)
+
+var (
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// MarshalJSON implements json.Marshaler.
+//
+// The map is encoded as a JSON object following the rules of encoding/json for Go maps:
+// keys of string kind are used directly, keys implementing encoding.TextMarshaler are marshaled,
+// and integer keys are formatted in decimal.
+// Entries are written in iteration order.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := m.EncodeJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeJSON writes the map to w as a JSON object, one entry at a time.
+// See MarshalJSON for the encoding of keys.
+func (m *Map[K, V]) EncodeJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteByte('{')
+
+	first := true
+	for k, v := range m.All() {
+		name, err := encodeJSONKey(k)
+		if err != nil {
+			return err
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return err
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+
+		if !first {
+			bw.WriteByte(',')
+		}
+		first = false
+		bw.Write(key)
+		bw.WriteByte(':')
+		bw.Write(value)
+	}
+
+	bw.WriteByte('}')
+	return bw.Flush()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+//
+// The entries of m are replaced with those of the JSON object,
+// and m keeps its hash function. A JSON null leaves m unchanged.
+// As with any Unmarshaler, m is modified in place, so it should not be shared while decoding.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	return m.DecodeJSON(json.NewDecoder(bytes.NewReader(data)))
+}
+
+// DecodeJSON reads the next JSON object from dec, one entry at a time,
+// and replaces the entries of m with it.
+// See UnmarshalJSON for details.
+func (m *Map[K, V]) DecodeJSON(dec *json.Decoder) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("champ: cannot unmarshal %v into %T", tok, m)
+	}
+
+	b := m.empty().Transient()
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, ok := tok.(string)
+		if !ok {
+			return fmt.Errorf("champ: unexpected object key %v", tok)
+		}
+		key, err := decodeJSONKey[K](name)
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		b.Set(key, value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	*m = *b.Persistent()
+	return nil
+}
+
+// encodeJSONKey returns the JSON object key for key.
+func encodeJSONKey[K comparable](key K) (string, error) {
+	if s, ok := any(key).(string); ok {
+		return s, nil
+	}
+
+	v := reflect.ValueOf(&key).Elem()
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return "", nil
+		}
+		text, err := any(key).(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+	return "", &json.UnsupportedTypeError{Type: v.Type()}
+}
+
+// decodeJSONKey parses a JSON object key into a key of type K.
+func decodeJSONKey[K comparable](name string) (K, error) {
+	var key K
+	if _, ok := any(key).(string); ok {
+		return any(name).(K), nil
+	}
+
+	v := reflect.ValueOf(&key).Elem()
+	if u, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := u.UnmarshalText([]byte(name))
+		return key, err
+	}
+	if v.Kind() == reflect.Pointer && reflect.PointerTo(v.Type().Elem()).Implements(textUnmarshalerType) {
+		v.Set(reflect.New(v.Type().Elem()))
+		err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(name))
+		return key, err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(name)
+		return key, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(name, 10, 64)
+		if err != nil || v.OverflowInt(n) {
+			return key, &json.UnmarshalTypeError{Value: "number " + name, Type: v.Type()}
+		}
+		v.SetInt(n)
+		return key, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(name, 10, 64)
+		if err != nil || v.OverflowUint(n) {
+			return key, &json.UnmarshalTypeError{Value: "number " + name, Type: v.Type()}
+		}
+		v.SetUint(n)
+		return key, nil
+	}
+	return key, errors.New("champ: unsupported map key type " + v.Type().String())
+}
@@ -0,0 +1,241 @@
+package champ
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		marshal  func() ([]byte, error)
+		expected string
+	}{
+		{
+			name:     "empty map",
+			marshal:  func() ([]byte, error) { return json.Marshal(New[string, int]()) },
+			expected: `{}`,
+		},
+		{
+			name: "string keys",
+			marshal: func() ([]byte, error) {
+				return json.Marshal(New[string, int]().Set("a", 1).Set("b", 2).Set("<c>", 3))
+			},
+			expected: `{"a":1,"b":2,"<c>":3}`,
+		},
+		{
+			name: "integer keys",
+			marshal: func() ([]byte, error) {
+				return json.Marshal(New[int8, string]().Set(-1, "minus").Set(100, "hundred"))
+			},
+			expected: `{"-1":"minus","100":"hundred"}`,
+		},
+		{
+			name: "text marshaler keys",
+			marshal: func() ([]byte, error) {
+				return json.Marshal(New[netip.Addr, bool]().Set(netip.MustParseAddr("10.0.0.1"), true))
+			},
+			expected: `{"10.0.0.1":true}`,
+		},
+		{
+			name: "nested maps",
+			marshal: func() ([]byte, error) {
+				return json.Marshal(New[string, *Map[uint, []int]]().Set("a", New[uint, []int]().Set(7, []int{1, 2})))
+			},
+			expected: `{"a":{"7":[1,2]}}`,
+		},
+		{
+			name: "embedded in struct",
+			marshal: func() ([]byte, error) {
+				return json.Marshal(struct {
+					Items *Map[string, int] `json:"items"`
+					Empty *Map[string, int] `json:"empty"`
+				}{Items: New[string, int]().Set("a", 1)})
+			},
+			expected: `{"items":{"a":1},"empty":null}`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.marshal()
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %v", err)
+			}
+
+			// entries are written in trie order, so compare the decoded objects
+			var actual, expected any
+			if err := json.Unmarshal(data, &actual); err != nil {
+				t.Fatalf("Marshal() produced invalid JSON %s: %v", data, err)
+			}
+			if err := json.Unmarshal([]byte(tt.expected), &expected); err != nil {
+				t.Fatal(err)
+			}
+			if fmt.Sprint(actual) != fmt.Sprint(expected) {
+				t.Errorf("Marshal() expected %s, actual %s", tt.expected, data)
+			}
+		})
+	}
+
+	t.Run("unsupported key type", func(t *testing.T) {
+		_, err := json.Marshal(New[struct{ A int }, int]().Set(struct{ A int }{1}, 1))
+		if err == nil {
+			t.Error("Marshal() expected error for struct keys")
+		}
+	})
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		m := New[string, int]()
+		expected := map[string]int{}
+		for i := range 1000 {
+			m = m.Set(fmt.Sprintf("key%d", i), i)
+			expected[fmt.Sprintf("key%d", i)] = i
+		}
+
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatalf("Marshal() unexpected error: %v", err)
+		}
+
+		var actual Map[string, int]
+		if err := json.Unmarshal(data, &actual); err != nil {
+			t.Fatalf("Unmarshal() unexpected error: %v", err)
+		}
+		if !Equal(&actual, m) {
+			t.Error("Unmarshal() result does not match the marshaled map")
+		}
+		if !maps.Equal(maps.Collect(actual.All()), expected) {
+			t.Error("Unmarshal() entries do not match")
+		}
+	})
+
+	t.Run("keeps hash function", func(t *testing.T) {
+		m := NewWithSeed[int, string](42)
+		if err := json.Unmarshal([]byte(`{"1":"a","2":"b"}`), m); err != nil {
+			t.Fatalf("Unmarshal() unexpected error: %v", err)
+		}
+		expected := NewWithSeed[int, string](42).Set(1, "a").Set(2, "b")
+		if !sameHasher(m, expected) || !Equal(m, expected) {
+			t.Error("Unmarshal() did not keep the hash function")
+		}
+	})
+
+	t.Run("replaces existing entries", func(t *testing.T) {
+		m := New[string, int]().Set("old", 1)
+		if err := json.Unmarshal([]byte(`{"new":2}`), m); err != nil {
+			t.Fatalf("Unmarshal() unexpected error: %v", err)
+		}
+		if !Equal(m, New[string, int]().Set("new", 2)) {
+			t.Errorf("Unmarshal() expected only the decoded entries, actual %v", maps.Collect(m.All()))
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		m := New[string, int]().Set("a", 1)
+		if err := json.Unmarshal([]byte(`null`), m); err != nil {
+			t.Fatalf("Unmarshal() unexpected error: %v", err)
+		}
+		if m.Len() != 1 {
+			t.Errorf("Unmarshal() of null expected map to be unchanged, actual Len() %d", m.Len())
+		}
+	})
+
+	t.Run("text unmarshaler keys", func(t *testing.T) {
+		var m Map[netip.Addr, int]
+		if err := json.Unmarshal([]byte(`{"10.0.0.1":1,"::1":2}`), &m); err != nil {
+			t.Fatalf("Unmarshal() unexpected error: %v", err)
+		}
+		if v, ok := m.Get(netip.MustParseAddr("::1")); !ok || v != 2 {
+			t.Errorf("Get() expected (2, true), actual (%d, %v)", v, ok)
+		}
+	})
+
+	t.Run("embedded in struct", func(t *testing.T) {
+		var s struct {
+			Items *Map[uint16, []string] `json:"items"`
+		}
+		if err := json.Unmarshal([]byte(`{"items":{"1":["a"],"65535":[]}}`), &s); err != nil {
+			t.Fatalf("Unmarshal() unexpected error: %v", err)
+		}
+		if s.Items.Len() != 2 {
+			t.Errorf("Len() expected %d, actual %d", 2, s.Items.Len())
+		}
+	})
+
+	for _, tt := range []struct {
+		name      string
+		data      string
+		unmarshal func(data []byte) error
+	}{
+		{
+			name:      "not an object",
+			data:      `[1, 2]`,
+			unmarshal: func(data []byte) error { return json.Unmarshal(data, New[string, int]()) },
+		},
+		{
+			name:      "invalid value",
+			data:      `{"a":"b"}`,
+			unmarshal: func(data []byte) error { return json.Unmarshal(data, New[string, int]()) },
+		},
+		{
+			name:      "integer key overflow",
+			data:      `{"128":1}`,
+			unmarshal: func(data []byte) error { return json.Unmarshal(data, New[int8, int]()) },
+		},
+		{
+			name:      "invalid integer key",
+			data:      `{"x":1}`,
+			unmarshal: func(data []byte) error { return json.Unmarshal(data, New[uint, int]()) },
+		},
+		{
+			name:      "invalid text key",
+			data:      `{"x":1}`,
+			unmarshal: func(data []byte) error { return json.Unmarshal(data, New[netip.Addr, int]()) },
+		},
+		{
+			name:      "unsupported key type",
+			data:      `{"x":1}`,
+			unmarshal: func(data []byte) error { return json.Unmarshal(data, New[float64, int]()) },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.unmarshal([]byte(tt.data)); err == nil {
+				t.Errorf("Unmarshal(%s) expected error", tt.data)
+			}
+		})
+	}
+}
+
+func TestJSONStreaming(t *testing.T) {
+	m1 := New[string, int]().Set("a", 1).Set("b", 2)
+	m2 := New[string, int]().Set("c", 3)
+
+	var buf bytes.Buffer
+	if err := m1.EncodeJSON(&buf); err != nil {
+		t.Fatalf("EncodeJSON() unexpected error: %v", err)
+	}
+	buf.WriteString("\n")
+	if err := m2.EncodeJSON(&buf); err != nil {
+		t.Fatalf("EncodeJSON() unexpected error: %v", err)
+	}
+
+	// decode consecutive objects from a single stream
+	dec := json.NewDecoder(strings.NewReader(buf.String()))
+	for _, expected := range []*Map[string, int]{m1, m2} {
+		actual := New[string, int]()
+		if err := actual.DecodeJSON(dec); err != nil {
+			t.Fatalf("DecodeJSON() unexpected error: %v", err)
+		}
+		if !Equal(actual, expected) {
+			t.Errorf("DecodeJSON() expected %v, actual %v", maps.Collect(expected.All()), maps.Collect(actual.All()))
+		}
+	}
+	if dec.More() {
+		t.Error("DecodeJSON() left unread data in the stream")
+	}
+}
@@ -3,6 +3,7 @@ package champ
 import (
 	"hash/maphash"
 	"iter"
+	"slices"
 )
 
 var (
@@ -20,8 +21,41 @@ const (
 
 // Map represents a CHAMP (Compressed Hash-Array Mapped Prefix-tree).
 type Map[K comparable, V any] struct {
-	root node[K, V]
-	size int
+	root   node[K, V]
+	size   int
+	hasher *hasher[K] // nil for the default hash function
+}
+
+// hasher holds a custom hash function shared by maps derived from the same constructor call.
+// Maps sharing a hasher lay out equal keys identically, which binary operations rely on.
+type hasher[K comparable] struct {
+	fn     func(key K) uint64
+	seed   Seed // seed of a deterministic hash function
+	seeded bool
+}
+
+// same reports whether h and other hash keys identically.
+func (h *hasher[K]) same(other *hasher[K]) bool {
+	if h == other {
+		return true
+	}
+	return h != nil && other != nil && h.seeded && other.seeded && h.seed == other.seed
+}
+
+// hash returns the hash of key. A nil hasher uses the default hash function.
+func (h *hasher[K]) hash(key K) uint64 {
+	if h == nil {
+		return hashKey(key)
+	}
+	return h.fn(key)
+}
+
+// hashFunc returns the hash function. A nil hasher uses the default hash function.
+func (h *hasher[K]) hashFunc() func(key K) uint64 {
+	if h == nil {
+		return hashKey[K]
+	}
+	return h.fn
 }
 
 // New creates a new empty CHAMP map.
@@ -32,18 +66,28 @@ func New[K comparable, V any]() *Map[K, V] {
 	}
 }
 
+// NewWithHasher creates a new empty CHAMP map which hashes keys with the given function.
+//
+// The function must return equal hashes for equal keys.
+// Maps derived from the returned map share the hash function.
+func NewWithHasher[K comparable, V any](hash func(key K) uint64) *Map[K, V] {
+	return &Map[K, V]{
+		hasher: &hasher[K]{fn: hash},
+	}
+}
+
 // Get retrieves a value by key.
 func (m *Map[K, V]) Get(key K) (V, bool) {
 	var zero V
 	if m.root == nil {
 		return zero, false
 	}
-	return m.root.get(key, hashKey(key), 0)
+	return nodeGet(m.root, key, m.hasher.hash(key), 0)
 }
 
 // Set sets or updates a key-value pair.
 func (m *Map[K, V]) Set(key K, value V) *Map[K, V] {
-	h := hashKey(key)
+	h := m.hasher.hash(key)
 
 	if m.root == nil {
 		return &Map[K, V]{
@@ -52,19 +96,21 @@ func (m *Map[K, V]) Set(key K, value V) *Map[K, V] {
 				keys:    []K{key},
 				values:  []V{value},
 			},
-			size: 1,
+			size:   1,
+			hasher: m.hasher,
 		}
 	}
 
-	root, added := m.root.set(key, value, h, 0, hashKey)
+	root, added := nodeSet(m.root, key, value, h, 0, m.hasher.hashFunc())
 	size := m.size
 	if added {
 		size++
 	}
 
 	return &Map[K, V]{
-		root: root,
-		size: size,
+		root:   root,
+		size:   size,
+		hasher: m.hasher,
 	}
 }
 
@@ -74,19 +120,20 @@ func (m *Map[K, V]) Delete(key K) *Map[K, V] {
 		return m
 	}
 
-	newRoot, deleted := m.root.del(key, hashKey(key), 0)
+	newRoot, deleted := nodeDel(m.root, key, m.hasher.hash(key), 0)
 	if !deleted {
 		return m
 	}
 
 	newSize := m.size - 1
 	if newRoot == nil {
-		return New[K, V]()
+		return m.empty()
 	}
 
 	return &Map[K, V]{
-		root: newRoot,
-		size: newSize,
+		root:   newRoot,
+		size:   newSize,
+		hasher: m.hasher,
 	}
 }
 
@@ -97,41 +144,85 @@ func (m *Map[K, V]) Len() int {
 
 // All returns an iterator over key-value pairs.
 func (m *Map[K, V]) All() iter.Seq2[K, V] {
-	if m.root != nil {
-		return m.root.all()
-	}
-	return func(func(K, V) bool) {}
+	return allNode(m.root)
 }
 
 func hashKey[K comparable](key K) uint64 {
 	return maphash.Comparable(seed, key)
 }
 
+// empty returns an empty map sharing the hash function of m.
+func (m *Map[K, V]) empty() *Map[K, V] {
+	return &Map[K, V]{hasher: m.hasher}
+}
+
+// withRoot returns a map with the given root and size sharing the hash function of m.
+// m itself is returned if the root is unchanged.
+func (m *Map[K, V]) withRoot(root node[K, V], size int) *Map[K, V] {
+	if root == m.root {
+		return m
+	}
+	if root == nil {
+		return m.empty()
+	}
+	return &Map[K, V]{
+		root:   root,
+		size:   size,
+		hasher: m.hasher,
+	}
+}
+
+// sameHasher reports whether m1 and m2 lay out equal keys identically,
+// so that their tries can be walked together.
+func sameHasher[K comparable, V, W any](m1 *Map[K, V], m2 *Map[K, W]) bool {
+	return m1.hasher.same(m2.hasher)
+}
+
 // Keys returns an iterator over the keys.
 func (m *Map[K, V]) Keys() iter.Seq[K] {
-	if m.root != nil {
-		return m.root.keysSeq()
-	}
-	return func(func(K) bool) {}
+	return keysNode(m.root)
 }
 
 // Values returns an iterator over the values.
 func (m *Map[K, V]) Values() iter.Seq[V] {
-	if m.root != nil {
-		return m.root.valuesSeq()
-	}
-	return func(func(V) bool) {}
+	return valuesNode(m.root)
 }
 
 // Equal checks if two maps contain the same key-value pairs.
+//
+// Maps sharing a hash function are compared structurally.
+// Otherwise every entry of m1 is looked up in m2.
 func Equal[K, V comparable](m1, m2 *Map[K, V]) bool {
+	return EqualFunc(m1, m2, equalValues[V])
+}
+
+// EqualFunc is like Equal but uses eq to compare values.
+//
+// Subtrees shared by both maps are skipped without calling eq.
+func EqualFunc[K comparable, V any](m1, m2 *Map[K, V], eq func(v1, v2 V) bool) bool {
 	if m1.size != m2.size {
 		return false
 	}
-	return equalNode(m1.root, m2.root)
+	if !sameHasher(m1, m2) {
+		for k, v1 := range m1.All() {
+			if v2, ok := m2.Get(k); !ok || !eq(v1, v2) {
+				return false
+			}
+		}
+		return true
+	}
+	return equalNodeFunc(m1.root, m2.root, eq)
+}
+
+func equalValues[V comparable](v1, v2 V) bool {
+	return v1 == v2
 }
 
 func equalNode[K, V comparable](n1, n2 node[K, V]) bool {
+	return equalNodeFunc(n1, n2, equalValues[V])
+}
+
+func equalNodeFunc[K comparable, V any](n1, n2 node[K, V], eq func(v1, v2 V) bool) bool {
 	// short-circuit for identical pointers
 	if n1 == n2 {
 		return true
@@ -143,48 +234,62 @@ func equalNode[K, V comparable](n1, n2 node[K, V]) bool {
 		if !ok {
 			return false
 		}
-		return equalBitmapIndexedNodes(n1, n2)
+		return equalBitmapIndexedNodes(n1, n2, eq)
 	case *collisionNode[K, V]:
 		n2, ok := n2.(*collisionNode[K, V])
 		if !ok {
 			return false
 		}
-		return equalCollisionNodes(n1, n2)
+		return equalCollisionNodes(n1, n2, eq)
 	}
 
 	return false
 }
 
-func equalBitmapIndexedNodes[K, V comparable](n1, n2 *bitmapIndexedNode[K, V]) bool {
+func equalBitmapIndexedNodes[K comparable, V any](n1, n2 *bitmapIndexedNode[K, V], eq func(v1, v2 V) bool) bool {
 	if n1.datamap != n2.datamap || n1.nodemap != n2.nodemap {
 		return false
 	}
 	for i := range n1.keys {
-		if n1.keys[i] != n2.keys[i] || n1.values[i] != n2.values[i] {
+		if n1.keys[i] != n2.keys[i] || !eq(n1.values[i], n2.values[i]) {
 			return false
 		}
 	}
 	for i := range n1.nodes {
-		if !equalNode(n1.nodes[i], n2.nodes[i]) {
+		if !equalNodeFunc(n1.nodes[i], n2.nodes[i], eq) {
 			return false
 		}
 	}
 	return true
 }
 
-func equalCollisionNodes[K, V comparable](n1, n2 *collisionNode[K, V]) bool {
+// collisionIndexThreshold is the number of entries above which collision nodes
+// are compared through an index instead of scanning for every key.
+const collisionIndexThreshold = 8
+
+// equalCollisionNodes compares the entries of two collision nodes regardless of their order.
+func equalCollisionNodes[K comparable, V any](n1, n2 *collisionNode[K, V], eq func(v1, v2 V) bool) bool {
 	if len(n1.keys) != len(n2.keys) {
 		return false
 	}
-	for i := range n1.keys {
-		ok := false
-		for j := range n2.keys {
-			if n1.keys[i] == n2.keys[j] && n1.values[i] == n2.values[j] {
-				ok = true
-				break
+
+	if len(n2.keys) <= collisionIndexThreshold {
+		for i := range n1.keys {
+			j := slices.Index(n2.keys, n1.keys[i])
+			if j < 0 || !eq(n1.values[i], n2.values[j]) {
+				return false
 			}
 		}
-		if !ok {
+		return true
+	}
+
+	index := make(map[K]int, len(n2.keys))
+	for j, k := range n2.keys {
+		index[k] = j
+	}
+	for i, k := range n1.keys {
+		j, ok := index[k]
+		if !ok || !eq(n1.values[i], n2.values[j]) {
 			return false
 		}
 	}
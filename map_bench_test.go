@@ -101,3 +101,110 @@ func BenchmarkMapDelete(b *testing.B) {
 		})
 	}
 }
+
+func BenchmarkBuilderSet(b *testing.B) {
+	sizes := []int{10, 100, 1000, 10000, 100000, 1000000}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			keys := make([]string, size)
+			for i := range size {
+				keys[i] = strconv.FormatInt(int64(i), 2)
+			}
+
+			b.ResetTimer()
+
+			for b.Loop() {
+				builder := NewBuilder[string, int]()
+				for i, key := range keys {
+					builder.Set(key, i)
+				}
+				_ = builder.Persistent()
+			}
+		})
+	}
+}
+
+func BenchmarkFromMap(b *testing.B) {
+	sizes := []int{10, 100, 1000, 10000, 100000, 1000000}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			entries := make(map[string]int, size)
+			for i := range size {
+				entries[strconv.FormatInt(int64(i), 2)] = i
+			}
+
+			b.ResetTimer()
+
+			for b.Loop() {
+				_ = FromMap(entries)
+			}
+		})
+	}
+}
+
+func BenchmarkMapAll(b *testing.B) {
+	sizes := []int{10, 100, 1000, 10000, 100000, 1000000}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			m := New[string, int]()
+			for i := range size {
+				m = m.Set(strconv.FormatInt(int64(i), 2), i)
+			}
+
+			b.ResetTimer()
+
+			for b.Loop() {
+				for k, v := range m.All() {
+					_, _ = k, v
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMerge(b *testing.B) {
+	sizes := []int{10, 100, 1000, 10000, 100000}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			m1 := New[string, int]()
+			m2 := New[string, int]()
+			for i := range size {
+				m1 = m1.Set(strconv.FormatInt(int64(i), 2), i)
+				m2 = m2.Set(strconv.FormatInt(int64(size/2+i), 2), i)
+			}
+
+			b.ResetTimer()
+
+			for b.Loop() {
+				_ = Merge(m1, m2, nil)
+			}
+		})
+	}
+}
+
+func BenchmarkIndexReindex(b *testing.B) {
+	sizes := []int{100, 10000, 1000000}
+
+	for _, size := range sizes {
+		b.Run(fmt.Sprintf("size_%d", size), func(b *testing.B) {
+			m := New[string, int]()
+			for i := range size {
+				m = m.Set(strconv.FormatInt(int64(i), 2), i)
+			}
+			x := m.Index()
+
+			b.ResetTimer()
+
+			for b.Loop() {
+				// index the next version after a single edit and sample from it
+				m = m.Set(strconv.FormatInt(int64(rand.IntN(size)), 2), 0)
+				x = x.Reindex(m)
+				_, _, _ = x.Sample(nil)
+			}
+		})
+	}
+}
@@ -63,5 +63,9 @@ func FuzzMap(f *testing.F) {
 				t.Errorf("Map.Len() = %d, but reference map has %d entries", m.Len(), len(reference))
 			}
 		}
+
+		if err := m.Validate(); err != nil {
+			t.Errorf("Map.Validate() = %v", err)
+		}
 	})
 }
@@ -428,3 +428,156 @@ func TestEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestNewWithHasher(t *testing.T) {
+	const n = 512
+
+	for _, tt := range []struct {
+		name string
+		hash func(key string) uint64
+	}{
+		{
+			name: "low entropy",
+			hash: func(key string) uint64 { return uint64(len(key)) },
+		},
+		{
+			name: "constant",
+			hash: func(string) uint64 { return 42 },
+		},
+		{
+			name: "colliding high bits",
+			hash: func(key string) uint64 { return uint64(key[len(key)-1]) << 60 },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewWithHasher[string, int](tt.hash)
+			for i := range n {
+				m = m.Set(fmt.Sprintf("key%d", i), i)
+			}
+
+			m = length(n)(t, m)
+			for i := range n {
+				m = get(fmt.Sprintf("key%d", i), i, true)(t, m)
+			}
+
+			for i := 0; i < n; i += 2 {
+				m = m.Delete(fmt.Sprintf("key%d", i))
+			}
+			m = length(n/2)(t, m)
+			for i := range n {
+				if i%2 == 0 {
+					m = get(fmt.Sprintf("key%d", i), 0, false)(t, m)
+				} else {
+					m = get(fmt.Sprintf("key%d", i), i, true)(t, m)
+				}
+			}
+
+			for i := range n {
+				m = m.Delete(fmt.Sprintf("key%d", i))
+			}
+			if m.root != nil {
+				t.Error("root is not nil after deleting all keys")
+			}
+			m = length(0)(t, m)
+			if m.hasher == nil {
+				t.Error("empty map lost its hash function")
+			}
+		})
+	}
+}
+
+func TestEqualWithHasher(t *testing.T) {
+	hash := func(key string) uint64 { return uint64(len(key)) }
+
+	m1 := NewWithHasher[string, int](hash)
+	m2 := NewWithHasher[string, int](hash)
+	m3 := New[string, int]()
+	for i := range 100 {
+		key := fmt.Sprintf("key%d", i)
+		m1 = m1.Set(key, i)
+		m2 = m2.Set(key, i)
+		m3 = m3.Set(key, i)
+	}
+
+	if !Equal(m1, m2) {
+		t.Error("Equal() = false for maps with separately constructed hash functions")
+	}
+	if !Equal(m1, m3) {
+		t.Error("Equal() = false for maps with different hash functions")
+	}
+	if Equal(m1, m3.Set("key0", -1)) {
+		t.Error("Equal() = true for maps with different values")
+	}
+}
+
+func TestEqualFunc(t *testing.T) {
+	eq := func(v1, v2 []int) bool { return slices.Equal(v1, v2) }
+
+	m1 := New[string, []int]()
+	for i := range 1000 {
+		m1 = m1.Set(fmt.Sprintf("key%d", i), []int{i, i})
+	}
+	m2 := New[string, []int]()
+	for i := 999; i >= 0; i-- {
+		m2 = m2.Set(fmt.Sprintf("key%d", i), []int{i, i})
+	}
+
+	if !EqualFunc(m1, m2, eq) {
+		t.Error("EqualFunc() = false for maps with equal values")
+	}
+	if EqualFunc(m1, m2.Set("key0", []int{0}), eq) {
+		t.Error("EqualFunc() = true for maps with different values")
+	}
+	if EqualFunc(m1, m2.Delete("key0"), eq) {
+		t.Error("EqualFunc() = true for maps with different sizes")
+	}
+
+	t.Run("shared subtrees are skipped", func(t *testing.T) {
+		calls := 0
+		counting := func(v1, v2 []int) bool {
+			calls++
+			return eq(v1, v2)
+		}
+		if !EqualFunc(m1, m1, counting) {
+			t.Error("EqualFunc() = false for identical maps")
+		}
+		if calls != 0 {
+			t.Errorf("EqualFunc() of identical maps called eq %d times", calls)
+		}
+
+		EqualFunc(m1, m1.Set("key0", []int{0}), counting)
+		if calls > branchFactor*maxDepth {
+			t.Errorf("EqualFunc() of maps differing in one entry called eq %d times", calls)
+		}
+	})
+}
+
+func TestEqualCollisionNodes(t *testing.T) {
+	for _, n := range []int{2, collisionIndexThreshold, collisionIndexThreshold + 1, 100} {
+		t.Run(fmt.Sprintf("size %d", n), func(t *testing.T) {
+			n1 := &collisionNode[string, int]{}
+			n2 := &collisionNode[string, int]{}
+			for i := range n {
+				n1.keys = append(n1.keys, fmt.Sprintf("key%d", i))
+				n1.values = append(n1.values, i)
+				n2.keys = append(n2.keys, fmt.Sprintf("key%d", n-1-i))
+				n2.values = append(n2.values, n-1-i)
+			}
+
+			if !equalNode[string, int](n1, n2) {
+				t.Error("equalNode() = false for collision nodes with entries in different order")
+			}
+
+			n2.values[0]++
+			if equalNode[string, int](n1, n2) {
+				t.Error("equalNode() = true for collision nodes with different values")
+			}
+			n2.values[0]--
+
+			n2.keys[0] = "missing"
+			if equalNode[string, int](n1, n2) {
+				t.Error("equalNode() = true for collision nodes with different keys")
+			}
+		})
+	}
+}
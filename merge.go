@@ -0,0 +1,196 @@
+package champ
+
+// Merge returns a map containing the entries of both m1 and m2.
+//
+// For keys present in both maps, the value is resolve(key, v1, v2),
+// where v1 comes from m1 and v2 from m2. If resolve is nil, the value of m2 wins.
+//
+// Merge walks both tries together and reuses subtrees present in only one of them.
+// Subtrees shared by m1 and m2 are reused as is without calling resolve,
+// so resolve(key, v, v) is assumed to return v.
+// If the maps use different hash functions, the entries of m2 are inserted one by one instead.
+// The result uses the hash function of m1.
+func Merge[K comparable, V any](m1, m2 *Map[K, V], resolve func(key K, v1, v2 V) V) *Map[K, V] {
+	if m2.root == nil {
+		return m1
+	}
+	if resolve == nil {
+		resolve = func(_ K, _, v2 V) V { return v2 }
+	}
+
+	if !sameHasher(m1, m2) {
+		b := m1.Transient()
+		for k, v2 := range m2.All() {
+			if v1, ok := b.Get(k); ok {
+				v2 = resolve(k, v1, v2)
+			}
+			b.Set(k, v2)
+		}
+		return b.Persistent()
+	}
+	if m1.root == nil {
+		return m2
+	}
+
+	root, dups := mergeNode(m1.root, m2.root, 0, m1.hasher.hashFunc(), resolve)
+	return &Map[K, V]{
+		root:   root,
+		size:   m1.size + m2.size - dups,
+		hasher: m1.hasher,
+	}
+}
+
+// mergeNode merges two nodes at the same position of the trie.
+// It returns the merged node and the number of keys present in both nodes.
+func mergeNode[K comparable, V any](
+	n1, n2 node[K, V],
+	shift uint,
+	hashFunc func(key K) uint64,
+	resolve func(key K, v1, v2 V) V,
+) (node[K, V], int) {
+	// short-circuit for identical pointers
+	if n1 == n2 {
+		return n1, countNode(n1)
+	}
+
+	switch n1 := n1.(type) {
+	case *bitmapIndexedNode[K, V]:
+		if n2, ok := n2.(*bitmapIndexedNode[K, V]); ok {
+			return mergeBitmapIndexedNodes(n1, n2, shift, hashFunc, resolve)
+		}
+	case *collisionNode[K, V]:
+		if n2, ok := n2.(*collisionNode[K, V]); ok {
+			return mergeCollisionNodes(n1, n2, resolve)
+		}
+	}
+
+	// Nodes of different kinds never meet in a canonical trie,
+	// but fall back to inserting the entries one by one.
+	n, dups := n1, 0
+	for k, v2 := range allNode(n2) {
+		n, dups = mergeEntry(n, k, v2, hashFunc(k), shift, hashFunc, resolve, dups)
+	}
+	return n, dups
+}
+
+// mergeEntry inserts the entry of m2 into the node of m1, resolving conflicts.
+func mergeEntry[K comparable, V any](
+	n node[K, V],
+	key K, v2 V, hash uint64,
+	shift uint,
+	hashFunc func(key K) uint64,
+	resolve func(key K, v1, v2 V) V,
+	dups int,
+) (node[K, V], int) {
+	if v1, ok := nodeGet(n, key, hash, shift); ok {
+		v2 = resolve(key, v1, v2)
+		dups++
+	}
+	n, _ = nodeSet(n, key, v2, hash, shift, hashFunc)
+	return n, dups
+}
+
+func mergeBitmapIndexedNodes[K comparable, V any](
+	n1, n2 *bitmapIndexedNode[K, V],
+	shift uint,
+	hashFunc func(key K) uint64,
+	resolve func(key K, v1, v2 V) V,
+) (node[K, V], int) {
+	result := &bitmapIndexedNode[K, V]{}
+	dups := 0
+
+	for bits := n1.datamap | n1.nodemap | n2.datamap | n2.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		switch {
+		case n1.datamap&bit != 0:
+			idx1 := popcount(n1.datamap & (bit - 1))
+			k1, v1 := n1.keys[idx1], n1.values[idx1]
+
+			switch {
+			case n2.datamap&bit != 0:
+				idx2 := popcount(n2.datamap & (bit - 1))
+				k2, v2 := n2.keys[idx2], n2.values[idx2]
+				if k1 == k2 {
+					result.appendData(bit, k1, resolve(k1, v1, v2))
+					dups++
+				} else {
+					result.appendNode(bit, result.createSubNode(
+						nil,
+						k1, v1, hashFunc(k1),
+						k2, v2, hashFunc(k2),
+						shift+bitsPerLevel,
+					))
+				}
+			case n2.nodemap&bit != 0:
+				// Insert the entry of m1 into the sub-node of m2.
+				child := n2.nodes[popcount(n2.nodemap&(bit-1))]
+				h := hashFunc(k1)
+				if v2, ok := nodeGet(child, k1, h, shift+bitsPerLevel); ok {
+					v1 = resolve(k1, v1, v2)
+					dups++
+				}
+				newChild, _ := nodeSet(child, k1, v1, h, shift+bitsPerLevel, hashFunc)
+				result.appendNode(bit, newChild)
+			default:
+				result.appendData(bit, k1, v1)
+			}
+
+		case n1.nodemap&bit != 0:
+			child := n1.nodes[popcount(n1.nodemap&(bit-1))]
+
+			switch {
+			case n2.datamap&bit != 0:
+				idx2 := popcount(n2.datamap & (bit - 1))
+				k2 := n2.keys[idx2]
+				newChild, d := mergeEntry(child, k2, n2.values[idx2], hashFunc(k2), shift+bitsPerLevel, hashFunc, resolve, 0)
+				result.appendNode(bit, newChild)
+				dups += d
+			case n2.nodemap&bit != 0:
+				newChild, d := mergeNode(child, n2.nodes[popcount(n2.nodemap&(bit-1))], shift+bitsPerLevel, hashFunc, resolve)
+				result.appendNode(bit, newChild)
+				dups += d
+			default:
+				result.appendNode(bit, child)
+			}
+
+		case n2.datamap&bit != 0:
+			idx2 := popcount(n2.datamap & (bit - 1))
+			result.appendData(bit, n2.keys[idx2], n2.values[idx2])
+
+		default:
+			result.appendNode(bit, n2.nodes[popcount(n2.nodemap&(bit-1))])
+		}
+	}
+
+	return result, dups
+}
+
+func mergeCollisionNodes[K comparable, V any](
+	n1, n2 *collisionNode[K, V],
+	resolve func(key K, v1, v2 V) V,
+) (node[K, V], int) {
+	keys := make([]K, len(n1.keys), len(n1.keys)+len(n2.keys))
+	values := make([]V, len(n1.values), len(n1.values)+len(n2.values))
+	copy(keys, n1.keys)
+	copy(values, n1.values)
+	dups := 0
+
+outer:
+	for i, k2 := range n2.keys {
+		for j, k1 := range n1.keys {
+			if k1 == k2 {
+				values[j] = resolve(k1, values[j], n2.values[i])
+				dups++
+				continue outer
+			}
+		}
+		keys = append(keys, k2)
+		values = append(values, n2.values[i])
+	}
+
+	return &collisionNode[K, V]{
+		keys:   keys,
+		values: values,
+	}, dups
+}
@@ -0,0 +1,218 @@
+package champ
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	sum := func(_ string, v1, v2 int) int { return v1 + v2 }
+
+	largeMap := func(start, end int) *Map[string, int] {
+		m := New[string, int]()
+		for i := start; i < end; i++ {
+			m = m.Set(fmt.Sprintf("key%d", i), i)
+		}
+		return m
+	}
+
+	for _, tt := range []struct {
+		name     string
+		maps     func() (*Map[string, int], *Map[string, int])
+		resolve  func(key string, v1, v2 int) int
+		expected func() *Map[string, int]
+	}{
+		{
+			name: "empty",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int](), New[string, int]()
+			},
+			resolve:  sum,
+			expected: New[string, int],
+		},
+		{
+			name: "one empty",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int](), New[string, int]().Set("a", 1)
+			},
+			resolve: sum,
+			expected: func() *Map[string, int] {
+				return New[string, int]().Set("a", 1)
+			},
+		},
+		{
+			name: "disjoint keys",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int]().Set("a", 1).Set("b", 2), New[string, int]().Set("c", 3)
+			},
+			resolve: sum,
+			expected: func() *Map[string, int] {
+				return New[string, int]().Set("a", 1).Set("b", 2).Set("c", 3)
+			},
+		},
+		{
+			name: "conflicting keys resolved",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int]().Set("a", 1).Set("b", 2), New[string, int]().Set("b", 20).Set("c", 3)
+			},
+			resolve: sum,
+			expected: func() *Map[string, int] {
+				return New[string, int]().Set("a", 1).Set("b", 22).Set("c", 3)
+			},
+		},
+		{
+			name: "nil resolve prefers second map",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int]().Set("a", 1).Set("b", 2), New[string, int]().Set("b", 20)
+			},
+			resolve: nil,
+			expected: func() *Map[string, int] {
+				return New[string, int]().Set("a", 1).Set("b", 20)
+			},
+		},
+		{
+			name: "large overlapping maps",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				return largeMap(0, 2000), largeMap(1000, 3000)
+			},
+			resolve: sum,
+			expected: func() *Map[string, int] {
+				m := largeMap(0, 3000)
+				for i := 1000; i < 2000; i++ {
+					m = m.Set(fmt.Sprintf("key%d", i), i*2)
+				}
+				return m
+			},
+		},
+		{
+			name: "versions sharing structure",
+			maps: func() (*Map[string, int], *Map[string, int]) {
+				m1 := largeMap(0, 2000)
+				m2 := m1
+				for i := 0; i < 2000; i += 100 {
+					m2 = m2.Set(fmt.Sprintf("key%d", i), -i)
+				}
+				return m1, m2.Set("extra", 1)
+			},
+			resolve: nil,
+			expected: func() *Map[string, int] {
+				m := largeMap(0, 2000)
+				for i := 0; i < 2000; i += 100 {
+					m = m.Set(fmt.Sprintf("key%d", i), -i)
+				}
+				return m.Set("extra", 1)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m1, m2 := tt.maps()
+			expected := tt.expected()
+
+			actual := Merge(m1, m2, tt.resolve)
+			if actual.Len() != expected.Len() {
+				t.Errorf("Len() expected %d, actual %d", expected.Len(), actual.Len())
+			}
+			if !Equal(actual, expected) {
+				t.Error("Merge() result does not match expected")
+			}
+		})
+	}
+}
+
+func TestMergeNode(t *testing.T) {
+	sum := func(_ string, v1, v2 int) int { return v1 + v2 }
+
+	for _, tt := range []struct {
+		name         string
+		n1, n2       node[string, int]
+		shift        uint
+		expectedDups int
+		expected     node[string, int]
+	}{
+		{
+			name: "data entry inserted into sub-node",
+			n1: &bitmapIndexedNode[string, int]{
+				datamap: 0b00010,
+				keys:    []string{"00001"},
+				values:  []int{100},
+			},
+			n2: &bitmapIndexedNode[string, int]{
+				nodemap: 0b00010,
+				nodes: []node[string, int]{
+					&bitmapIndexedNode[string, int]{
+						datamap: 0b00110,
+						keys:    []string{"0000100001", "0001000001"},
+						values:  []int{200, 300},
+					},
+				},
+			},
+			expectedDups: 0,
+			expected: &bitmapIndexedNode[string, int]{
+				nodemap: 0b00010,
+				nodes: []node[string, int]{
+					&bitmapIndexedNode[string, int]{
+						datamap: 0b00111,
+						keys:    []string{"00001", "0000100001", "0001000001"},
+						values:  []int{100, 200, 300},
+					},
+				},
+			},
+		},
+		{
+			name: "sub-node entry resolved with data entry",
+			n1: &bitmapIndexedNode[string, int]{
+				nodemap: 0b00010,
+				nodes: []node[string, int]{
+					&bitmapIndexedNode[string, int]{
+						datamap: 0b00110,
+						keys:    []string{"0000100001", "0001000001"},
+						values:  []int{200, 300},
+					},
+				},
+			},
+			n2: &bitmapIndexedNode[string, int]{
+				datamap: 0b00010,
+				keys:    []string{"0000100001"},
+				values:  []int{1},
+			},
+			expectedDups: 1,
+			expected: &bitmapIndexedNode[string, int]{
+				nodemap: 0b00010,
+				nodes: []node[string, int]{
+					&bitmapIndexedNode[string, int]{
+						datamap: 0b00110,
+						keys:    []string{"0000100001", "0001000001"},
+						values:  []int{201, 300},
+					},
+				},
+			},
+		},
+		{
+			name: "collision nodes",
+			n1: &collisionNode[string, int]{
+				keys:   []string{"1", "01"},
+				values: []int{100, 200},
+			},
+			n2: &collisionNode[string, int]{
+				keys:   []string{"01", "001"},
+				values: []int{1, 300},
+			},
+			shift:        5 * 13,
+			expectedDups: 1,
+			expected: &collisionNode[string, int]{
+				keys:   []string{"1", "01", "001"},
+				values: []int{100, 201, 300},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, dups := mergeNode(tt.n1, tt.n2, tt.shift, testHashFunc, sum)
+			if dups != tt.expectedDups {
+				t.Errorf("mergeNode() dups = %d, expected %d", dups, tt.expectedDups)
+			}
+			if !equalNode(result, tt.expected) {
+				t.Errorf("mergeNode() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
+			}
+		})
+	}
+}
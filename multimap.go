@@ -0,0 +1,130 @@
+package champ
+
+import "iter"
+
+// Multimap represents a persistent map from keys to sets of values.
+//
+// A key is present as long as it has at least one value,
+// so removing the last value of a key also removes the key.
+// The zero value is an empty multimap ready to use.
+type Multimap[K, V comparable] struct {
+	m    Map[K, *Set[V]]
+	size int
+}
+
+// NewMultimap creates a new empty Multimap.
+func NewMultimap[K, V comparable]() *Multimap[K, V] {
+	return &Multimap[K, V]{}
+}
+
+// Add returns a multimap in which key is also associated with value.
+// If the pair is already present, m itself is returned.
+func (m *Multimap[K, V]) Add(key K, value V) *Multimap[K, V] {
+	values, ok := m.m.Get(key)
+	if !ok {
+		values = &Set[V]{}
+	}
+	newValues := values.Add(value)
+	if newValues == values {
+		return m
+	}
+	return &Multimap[K, V]{
+		m:    *m.m.Set(key, newValues),
+		size: m.size + 1,
+	}
+}
+
+// Remove returns a multimap without the pair of key and value.
+// If the pair is not present, m itself is returned.
+func (m *Multimap[K, V]) Remove(key K, value V) *Multimap[K, V] {
+	values, ok := m.m.Get(key)
+	if !ok {
+		return m
+	}
+	newValues := values.Remove(value)
+	if newValues == values {
+		return m
+	}
+	if newValues.Len() == 0 {
+		return &Multimap[K, V]{
+			m:    *m.m.Delete(key),
+			size: m.size - 1,
+		}
+	}
+	return &Multimap[K, V]{
+		m:    *m.m.Set(key, newValues),
+		size: m.size - 1,
+	}
+}
+
+// RemoveAll returns a multimap without key and all of its values.
+// If key is not present, m itself is returned.
+func (m *Multimap[K, V]) RemoveAll(key K) *Multimap[K, V] {
+	values, ok := m.m.Get(key)
+	if !ok {
+		return m
+	}
+	return &Multimap[K, V]{
+		m:    *m.m.Delete(key),
+		size: m.size - values.Len(),
+	}
+}
+
+// Get returns an iterator over the values associated with key.
+func (m *Multimap[K, V]) Get(key K) iter.Seq[V] {
+	return func(yield func(V) bool) {
+		values, ok := m.m.Get(key)
+		if !ok {
+			return
+		}
+		for v := range values.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Contains reports whether key is associated with value.
+func (m *Multimap[K, V]) Contains(key K, value V) bool {
+	values, ok := m.m.Get(key)
+	return ok && values.Contains(value)
+}
+
+// Count returns the number of values associated with key.
+func (m *Multimap[K, V]) Count(key K) int {
+	values, ok := m.m.Get(key)
+	if !ok {
+		return 0
+	}
+	return values.Len()
+}
+
+// Len returns the number of key-value pairs
+func (m *Multimap[K, V]) Len() int {
+	return m.size
+}
+
+// KeyLen returns the number of distinct keys
+func (m *Multimap[K, V]) KeyLen() int {
+	return m.m.Len()
+}
+
+// All returns an iterator over key-value pairs.
+// Each key is yielded once per associated value.
+func (m *Multimap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for k, values := range m.m.All() {
+			for v := range values.All() {
+				if !yield(k, v) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over the distinct keys.
+func (m *Multimap[K, V]) Keys() iter.Seq[K] {
+	return m.m.Keys()
+}
@@ -0,0 +1,102 @@
+package champ
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestMultimap(t *testing.T) {
+	t.Run("basic operations", func(t *testing.T) {
+		var m Multimap[string, int]
+
+		m1 := m.Add("a", 1).Add("a", 2).Add("b", 1)
+		if m1.Len() != 3 {
+			t.Fatalf("Len() expected %d, actual %d", 3, m1.Len())
+		}
+		if m1.KeyLen() != 2 {
+			t.Fatalf("KeyLen() expected %d, actual %d", 2, m1.KeyLen())
+		}
+		if m2 := m1.Add("a", 1); m2 != m1 {
+			t.Error("Add() of an existing pair returned a new multimap")
+		}
+		if actual := slices.Sorted(m1.Get("a")); !slices.Equal(actual, []int{1, 2}) {
+			t.Errorf("Get(%q) expected %v, actual %v", "a", []int{1, 2}, actual)
+		}
+		if actual := m1.Count("a"); actual != 2 {
+			t.Errorf("Count(%q) expected %d, actual %d", "a", 2, actual)
+		}
+
+		m3 := m1.Remove("a", 1)
+		if m3.Len() != 2 {
+			t.Fatalf("Len() expected %d, actual %d", 2, m3.Len())
+		}
+		if m3.Contains("a", 1) {
+			t.Error("Contains() expected false after Remove")
+		}
+		if !m1.Contains("a", 1) {
+			t.Error("Contains() expected true on original multimap")
+		}
+		if m4 := m3.Remove("a", 1); m4 != m3 {
+			t.Error("Remove() of a missing pair returned a new multimap")
+		}
+		if m4 := m3.Remove("missing", 1); m4 != m3 {
+			t.Error("Remove() of a missing key returned a new multimap")
+		}
+	})
+
+	t.Run("removing the last value removes the key", func(t *testing.T) {
+		m := NewMultimap[string, int]().Add("a", 1).Add("b", 2).Remove("a", 1)
+		if m.KeyLen() != 1 {
+			t.Errorf("KeyLen() expected %d, actual %d", 1, m.KeyLen())
+		}
+		if _, ok := m.m.Get("a"); ok {
+			t.Error("empty value set was left in the multimap")
+		}
+		if actual := slices.Collect(m.Keys()); !slices.Equal(actual, []string{"b"}) {
+			t.Errorf("Keys() expected %v, actual %v", []string{"b"}, actual)
+		}
+	})
+
+	t.Run("remove all", func(t *testing.T) {
+		m := NewMultimap[string, int]()
+		for i := range 100 {
+			m = m.Add("a", i).Add("b", i)
+		}
+
+		m1 := m.RemoveAll("a")
+		if m1.Len() != 100 {
+			t.Errorf("Len() expected %d, actual %d", 100, m1.Len())
+		}
+		if m1.Count("a") != 0 {
+			t.Errorf("Count(%q) expected %d, actual %d", "a", 0, m1.Count("a"))
+		}
+		if m2 := m1.RemoveAll("a"); m2 != m1 {
+			t.Error("RemoveAll() of a missing key returned a new multimap")
+		}
+	})
+
+	t.Run("all pairs", func(t *testing.T) {
+		m := NewMultimap[int, int]()
+		var expected []string
+		for i := range 100 {
+			for j := range i % 5 {
+				m = m.Add(i, j)
+				expected = append(expected, fmt.Sprintf("%d:%d", i, j))
+			}
+		}
+
+		var actual []string
+		for k, v := range m.All() {
+			actual = append(actual, fmt.Sprintf("%d:%d", k, v))
+		}
+		slices.Sort(actual)
+		slices.Sort(expected)
+		if !slices.Equal(actual, expected) {
+			t.Errorf("All() expected %v, actual %v", expected, actual)
+		}
+		if m.Len() != len(expected) {
+			t.Errorf("Len() expected %d, actual %d", len(expected), m.Len())
+		}
+	})
+}
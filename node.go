@@ -2,108 +2,243 @@ package champ
 
 import (
 	"fmt"
-	"iter"
 	"slices"
 )
 
-type node[K comparable, V any] interface {
+// node is a bitmapIndexedNode or a collisionNode.
+//
+// The node types accept keys of any type, so that HashMap can store keys which are not comparable with ==.
+// The operations of Map below compare keys with ==, which the compiler resolves for each key type,
+// and the operations of HashMap in hashmap.go walk the same nodes comparing keys with Equal.
+type node[K any, V any] interface {
 	fmt.Stringer
 
-	get(key K, hash uint64, shift uint) (V, bool)
-	set(key K, value V, hash uint64, shift uint, hashFunc func(key K) uint64) (node[K, V], bool)
-	del(key K, hash uint64, shift uint) (node[K, V], bool)
-	all() iter.Seq2[K, V]
-	keysSeq() iter.Seq[K]
-	valuesSeq() iter.Seq[V]
+	// entries returns the keys and values stored in the node itself, excluding its sub-nodes.
+	entries() ([]K, []V)
 }
 
 // bitmapIndexedNode is the main CHAMP node type with compressed storage
-type bitmapIndexedNode[K comparable, V any] struct {
+type bitmapIndexedNode[K any, V any] struct {
 	nodemap uint32       // Bitmap for child nodes
 	datamap uint32       // Bitmap for key-value pairs
 	nodes   []node[K, V] // Array of child nodes (compressed)
 	keys    []K          // Array of keys (compressed)
 	values  []V          // Array of values (compressed)
+	edit    *editToken   // Builder allowed to mutate this node in place
 }
 
-func (n *bitmapIndexedNode[K, V]) get(key K, hash uint64, shift uint) (V, bool) {
+// nodeGet retrieves the value of key from the subtree n at shift.
+func nodeGet[K comparable, V any](n node[K, V], key K, hash uint64, shift uint) (V, bool) {
 	var zero V
-	bit := uint32(1 << ((hash >> shift) & bitMask))
-
-	if n.datamap&bit != 0 {
-		idx := popcount(n.datamap & (bit - 1))
-		if n.keys[idx] == key {
-			return n.values[idx], true
+	for {
+		switch nn := n.(type) {
+		case *bitmapIndexedNode[K, V]:
+			bit := uint32(1 << ((hash >> shift) & bitMask))
+			if nn.datamap&bit != 0 {
+				idx := popcount(nn.datamap & (bit - 1))
+				if nn.keys[idx] == key {
+					return nn.values[idx], true
+				}
+				return zero, false
+			}
+			if nn.nodemap&bit == 0 {
+				return zero, false
+			}
+			n = nn.nodes[popcount(nn.nodemap&(bit-1))]
+			shift += bitsPerLevel
+		case *collisionNode[K, V]:
+			if i := slices.Index(nn.keys, key); i >= 0 {
+				return nn.values[i], true
+			}
+			return zero, false
+		default:
+			return zero, false
 		}
-		return zero, false
 	}
+}
 
-	if n.nodemap&bit != 0 {
-		idx := popcount(n.nodemap & (bit - 1))
-		return n.nodes[idx].get(key, hash, shift+bitsPerLevel)
-	}
+// nodeSet returns the subtree n at shift with key set to value, and whether key was added.
+func nodeSet[K comparable, V any](
+	n node[K, V],
+	key K,
+	value V,
+	hash uint64,
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], bool) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		bit := uint32(1 << ((hash >> shift) & bitMask))
+
+		if n.datamap&bit != 0 {
+			idx := popcount(n.datamap & (bit - 1))
+			if n.keys[idx] == key {
+				return n.withValue(idx, value), false
+			}
+			return n.withSubNode(bit, key, value, hash, shift, hashFunc), true
+		}
 
-	return zero, false
+		if n.nodemap&bit != 0 {
+			child := n.nodes[popcount(n.nodemap&(bit-1))]
+			newChild, added := nodeSet(child, key, value, hash, shift+bitsPerLevel, hashFunc)
+			return n.withChild(bit, newChild), added
+		}
+
+		return n.withData(bit, key, value), true
+	case *collisionNode[K, V]:
+		if i := slices.Index(n.keys, key); i >= 0 {
+			return n.withValue(i, value), false
+		}
+		return n.withEntry(key, value), true
+	}
+	return n, false
 }
 
-func (n *bitmapIndexedNode[K, V]) set(key K, value V, hash uint64, shift uint, hashFunc func(key K) uint64) (node[K, V], bool) {
-	bit := uint32(1 << ((hash >> shift) & bitMask))
+// nodeDel returns the subtree n at shift without key, and whether key was present.
+// nil is returned if no entry remains.
+func nodeDel[K comparable, V any](n node[K, V], key K, hash uint64, shift uint) (node[K, V], bool) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		bit := uint32(1 << ((hash >> shift) & bitMask))
 
-	if n.datamap&bit != 0 {
-		idx := popcount(n.datamap & (bit - 1))
-		if n.keys[idx] == key {
-			newKeys := make([]K, len(n.keys))
-			copy(newKeys, n.keys)
-			newValues := make([]V, len(n.values))
-			copy(newValues, n.values)
-			newValues[idx] = value
+		if n.datamap&bit != 0 {
+			if n.keys[popcount(n.datamap&(bit-1))] != key {
+				return n, false
+			}
+			return n.withoutData(bit), true
+		}
 
-			return &bitmapIndexedNode[K, V]{
-				nodemap: n.nodemap,
-				datamap: n.datamap,
-				nodes:   n.nodes,
-				keys:    newKeys,
-				values:  newValues,
-			}, false
+		if n.nodemap&bit != 0 {
+			newChild, deleted := nodeDel(n.nodes[popcount(n.nodemap&(bit-1))], key, hash, shift+bitsPerLevel)
+			if !deleted {
+				return n, false
+			}
+			return n.withChild(bit, newChild), true
 		}
 
-		// Collision
-		subNode := n.createSubNode(
-			n.keys[idx], n.values[idx], hashFunc(n.keys[idx]),
-			key, value, hash,
-			shift+bitsPerLevel,
-		)
-		return &bitmapIndexedNode[K, V]{
-			nodemap: n.nodemap | bit,
-			datamap: n.datamap &^ bit,
-			nodes:   insertAt(n.nodes, popcount(n.nodemap&(bit-1)), subNode),
-			keys:    removeAt(n.keys, idx),
-			values:  removeAt(n.values, idx),
-		}, true
+		return n, false
+	case *collisionNode[K, V]:
+		if i := slices.Index(n.keys, key); i >= 0 {
+			return n.without(i), true
+		}
+		return n, false
 	}
+	return n, false
+}
 
-	// Check if we have a node at this position
-	if n.nodemap&bit != 0 {
-		idx := popcount(n.nodemap & (bit - 1))
-		newNode, added := n.nodes[idx].set(key, value, hash, shift+bitsPerLevel, hashFunc)
-		if !added && newNode == n.nodes[idx] {
-			return n, false
+// nodeUpdate applies fn to the entry of key in a single descent, and returns the new node and the change in size.
+// n itself is returned if fn leaves the entry as is.
+func nodeUpdate[K comparable, V any](
+	n node[K, V],
+	key K,
+	hash uint64,
+	shift uint,
+	fn updateFunc[V],
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		bit := uint32(1 << ((hash >> shift) & bitMask))
+
+		if n.nodemap&bit != 0 {
+			child := n.nodes[popcount(n.nodemap&(bit-1))]
+			newChild, delta := nodeUpdate(child, key, hash, shift+bitsPerLevel, fn, hashFunc)
+			if newChild == child {
+				return n, 0
+			}
+			return n.withChild(bit, newChild), delta
 		}
 
-		newNodes := make([]node[K, V], len(n.nodes))
-		copy(newNodes, n.nodes)
-		newNodes[idx] = newNode
+		idx := -1
+		if n.datamap&bit != 0 {
+			if i := popcount(n.datamap & (bit - 1)); n.keys[i] == key {
+				idx = i
+			}
+		}
+		return n.updateData(bit, idx, key, hash, shift, fn, hashFunc)
+	case *collisionNode[K, V]:
+		return n.updateEntry(slices.Index(n.keys, key), key, fn)
+	}
+	return n, 0
+}
+
+// updateData applies fn to the entry of key at bit of n, found at idx or absent if idx is negative,
+// and returns the new node and the change in size.
+// The entry, if present, is stored in n, so the new node is built without descending again.
+func (n *bitmapIndexedNode[K, V]) updateData(
+	bit uint32,
+	idx int,
+	key K,
+	hash uint64,
+	shift uint,
+	fn updateFunc[V],
+	hashFunc func(key K) uint64,
+) (node[K, V], int) {
+	var old V
+	if idx >= 0 {
+		old = n.values[idx]
+	}
 
-		return &bitmapIndexedNode[K, V]{
-			nodemap: n.nodemap,
-			datamap: n.datamap,
-			nodes:   newNodes,
-			keys:    n.keys,
-			values:  n.values,
-		}, added
+	value, action := fn(old, idx >= 0)
+	switch {
+	case action == UpdateSet && idx >= 0:
+		return n.withValue(idx, value), 0
+	case action == UpdateSet && n.datamap&bit != 0:
+		return n.withSubNode(bit, key, value, hash, shift, hashFunc), 1
+	case action == UpdateSet:
+		return n.withData(bit, key, value), 1
+	case action == UpdateDelete && idx >= 0:
+		return n.withoutData(bit), -1
+	default:
+		return n, 0
+	}
+}
+
+// withValue returns a copy of n with the value of the entry at idx replaced.
+func (n *bitmapIndexedNode[K, V]) withValue(idx int, value V) *bitmapIndexedNode[K, V] {
+	newKeys := make([]K, len(n.keys))
+	copy(newKeys, n.keys)
+	newValues := make([]V, len(n.values))
+	copy(newValues, n.values)
+	newValues[idx] = value
+
+	return &bitmapIndexedNode[K, V]{
+		nodemap: n.nodemap,
+		datamap: n.datamap,
+		nodes:   n.nodes,
+		keys:    newKeys,
+		values:  newValues,
 	}
+}
 
-	// Empty position
+// withSubNode returns a copy of n with the entry at bit, whose key differs from key,
+// moved into a new sub-node along with key and value.
+func (n *bitmapIndexedNode[K, V]) withSubNode(
+	bit uint32,
+	key K,
+	value V,
+	hash uint64,
+	shift uint,
+	hashFunc func(key K) uint64,
+) *bitmapIndexedNode[K, V] {
+	idx := popcount(n.datamap & (bit - 1))
+	subNode := n.createSubNode(
+		nil,
+		n.keys[idx], n.values[idx], hashFunc(n.keys[idx]),
+		key, value, hash,
+		shift+bitsPerLevel,
+	)
+	return &bitmapIndexedNode[K, V]{
+		nodemap: n.nodemap | bit,
+		datamap: n.datamap &^ bit,
+		nodes:   insertAt(n.nodes, popcount(n.nodemap&(bit-1)), subNode),
+		keys:    removeAt(n.keys, idx),
+		values:  removeAt(n.values, idx),
+	}
+}
+
+// withData returns a copy of n with an entry added at bit, which must be empty.
+func (n *bitmapIndexedNode[K, V]) withData(bit uint32, key K, value V) *bitmapIndexedNode[K, V] {
 	idx := popcount(n.datamap & (bit - 1))
 	return &bitmapIndexedNode[K, V]{
 		nodemap: n.nodemap,
@@ -111,130 +246,73 @@ func (n *bitmapIndexedNode[K, V]) set(key K, value V, hash uint64, shift uint, h
 		nodes:   n.nodes,
 		keys:    insertAt(n.keys, idx, key),
 		values:  insertAt(n.values, idx, value),
-	}, true
+	}
 }
 
-func (n *bitmapIndexedNode[K, V]) del(key K, hash uint64, shift uint) (node[K, V], bool) {
-	bit := uint32(1 << ((hash >> shift) & bitMask))
-
-	if n.datamap&bit != 0 {
-		idx := popcount(n.datamap & (bit - 1))
-		if n.keys[idx] != key {
-			return n, false
-		}
-
-		if len(n.keys) == 1 && len(n.nodes) == 0 {
-			return nil, true
-		}
-
-		return &bitmapIndexedNode[K, V]{
-			nodemap: n.nodemap,
-			datamap: n.datamap &^ bit,
-			nodes:   n.nodes,
-			keys:    removeAt(n.keys, idx),
-			values:  removeAt(n.values, idx),
-		}, true
+// withoutData returns a copy of n without the entry at bit.
+// nil is returned if no entry remains.
+func (n *bitmapIndexedNode[K, V]) withoutData(bit uint32) node[K, V] {
+	if len(n.keys) == 1 && len(n.nodes) == 0 {
+		return nil
 	}
 
-	if n.nodemap&bit != 0 {
-		idx := popcount(n.nodemap & (bit - 1))
-		newNode, deleted := n.nodes[idx].del(key, hash, shift+bitsPerLevel)
-
-		if !deleted {
-			return n, false
-		}
-
-		if newNode == nil {
-			// Remove empty node
-			if len(n.nodes) == 1 && len(n.keys) == 0 {
-				return nil, true
-			}
+	idx := popcount(n.datamap & (bit - 1))
+	return &bitmapIndexedNode[K, V]{
+		nodemap: n.nodemap,
+		datamap: n.datamap &^ bit,
+		nodes:   n.nodes,
+		keys:    removeAt(n.keys, idx),
+		values:  removeAt(n.values, idx),
+	}
+}
 
-			return &bitmapIndexedNode[K, V]{
-				nodemap: n.nodemap &^ bit,
-				datamap: n.datamap,
-				nodes:   removeAt(n.nodes, idx),
-				keys:    n.keys,
-				values:  n.values,
-			}, true
-		}
+// withChild returns a copy of n with the sub-node at bit replaced by child.
+// A nil child is removed, and a child holding a single entry is inlined as data.
+// nil is returned if no entry remains.
+func (n *bitmapIndexedNode[K, V]) withChild(bit uint32, child node[K, V]) node[K, V] {
+	idx := popcount(n.nodemap & (bit - 1))
 
-		if m, ok := newNode.(*bitmapIndexedNode[K, V]); ok && m.nodemap == 0 && len(m.keys) == 1 {
-			// Collapse single entry node
-			return &bitmapIndexedNode[K, V]{
-				nodemap: n.nodemap &^ bit,
-				datamap: n.datamap | bit,
-				nodes:   removeAt(n.nodes, idx),
-				keys:    insertAt(n.keys, popcount(n.datamap&(bit-1)), m.keys[0]),
-				values:  insertAt(n.values, popcount(n.datamap&(bit-1)), m.values[0]),
-			}, true
+	if child == nil {
+		// Remove empty node
+		if len(n.nodes) == 1 && len(n.keys) == 0 {
+			return nil
 		}
 
-		newNodes := make([]node[K, V], len(n.nodes))
-		copy(newNodes, n.nodes)
-		newNodes[idx] = newNode
-
 		return &bitmapIndexedNode[K, V]{
-			nodemap: n.nodemap,
+			nodemap: n.nodemap &^ bit,
 			datamap: n.datamap,
-			nodes:   newNodes,
+			nodes:   removeAt(n.nodes, idx),
 			keys:    n.keys,
 			values:  n.values,
-		}, true
+		}
 	}
 
-	return n, false
-}
-
-func (n *bitmapIndexedNode[K, V]) all() iter.Seq2[K, V] {
-	return func(yield func(K, V) bool) {
-		for i := 0; i < len(n.keys); i++ {
-			if !yield(n.keys[i], n.values[i]) {
-				return
-			}
-		}
-		for _, child := range n.nodes {
-			for k, v := range child.all() {
-				if !yield(k, v) {
-					return
-				}
-			}
+	if m, ok := child.(*bitmapIndexedNode[K, V]); ok && m.nodemap == 0 && len(m.keys) == 1 {
+		// Collapse single entry node
+		return &bitmapIndexedNode[K, V]{
+			nodemap: n.nodemap &^ bit,
+			datamap: n.datamap | bit,
+			nodes:   removeAt(n.nodes, idx),
+			keys:    insertAt(n.keys, popcount(n.datamap&(bit-1)), m.keys[0]),
+			values:  insertAt(n.values, popcount(n.datamap&(bit-1)), m.values[0]),
 		}
 	}
-}
 
-func (n *bitmapIndexedNode[K, V]) keysSeq() iter.Seq[K] {
-	return func(yield func(K) bool) {
-		for _, k := range n.keys {
-			if !yield(k) {
-				return
-			}
-		}
-		for _, child := range n.nodes {
-			for k := range child.keysSeq() {
-				if !yield(k) {
-					return
-				}
-			}
-		}
+	newNodes := make([]node[K, V], len(n.nodes))
+	copy(newNodes, n.nodes)
+	newNodes[idx] = child
+
+	return &bitmapIndexedNode[K, V]{
+		nodemap: n.nodemap,
+		datamap: n.datamap,
+		nodes:   newNodes,
+		keys:    n.keys,
+		values:  n.values,
 	}
 }
 
-func (n *bitmapIndexedNode[K, V]) valuesSeq() iter.Seq[V] {
-	return func(yield func(V) bool) {
-		for _, v := range n.values {
-			if !yield(v) {
-				return
-			}
-		}
-		for _, child := range n.nodes {
-			for v := range child.valuesSeq() {
-				if !yield(v) {
-					return
-				}
-			}
-		}
-	}
+func (n *bitmapIndexedNode[K, V]) entries() ([]K, []V) {
+	return n.keys, n.values
 }
 
 func (n *bitmapIndexedNode[K, V]) String() string {
@@ -242,6 +320,7 @@ func (n *bitmapIndexedNode[K, V]) String() string {
 }
 
 func (n *bitmapIndexedNode[K, V]) createSubNode(
+	edit *editToken,
 	key1 K, val1 V, hash1 uint64,
 	key2 K, val2 V, hash2 uint64,
 	shift uint,
@@ -251,6 +330,7 @@ func (n *bitmapIndexedNode[K, V]) createSubNode(
 		return &collisionNode[K, V]{
 			keys:   []K{key1, key2},
 			values: []V{val1, val2},
+			edit:   edit,
 		}
 	}
 
@@ -259,10 +339,11 @@ func (n *bitmapIndexedNode[K, V]) createSubNode(
 
 	if bit1 == bit2 {
 		// Same position at this level, recurse
-		subNode := n.createSubNode(key1, val1, hash1, key2, val2, hash2, shift+bitsPerLevel)
+		subNode := n.createSubNode(edit, key1, val1, hash1, key2, val2, hash2, shift+bitsPerLevel)
 		return &bitmapIndexedNode[K, V]{
 			nodemap: bit1,
 			nodes:   []node[K, V]{subNode},
+			edit:    edit,
 		}
 	}
 
@@ -271,47 +352,95 @@ func (n *bitmapIndexedNode[K, V]) createSubNode(
 			datamap: bit1 | bit2,
 			keys:    []K{key1, key2},
 			values:  []V{val1, val2},
+			edit:    edit,
 		}
 	}
 	return &bitmapIndexedNode[K, V]{
 		datamap: bit1 | bit2,
 		keys:    []K{key2, key1},
 		values:  []V{val2, val1},
+		edit:    edit,
+	}
+}
+
+// appendData adds a key-value pair at bit, which must be higher than any bit already set.
+func (n *bitmapIndexedNode[K, V]) appendData(bit uint32, key K, value V) {
+	n.datamap |= bit
+	n.keys = append(n.keys, key)
+	n.values = append(n.values, value)
+}
+
+// appendNode adds a sub-node at bit, which must be higher than any bit already set.
+func (n *bitmapIndexedNode[K, V]) appendNode(bit uint32, child node[K, V]) {
+	n.nodemap |= bit
+	n.nodes = append(n.nodes, child)
+}
+
+// appendChild adds a sub-node at bit, which must be higher than any bit already set.
+// A nil child is dropped, and a child holding a single entry is inlined as data.
+func (n *bitmapIndexedNode[K, V]) appendChild(bit uint32, child node[K, V]) {
+	switch c := child.(type) {
+	case nil:
+		return
+	case *bitmapIndexedNode[K, V]:
+		if c.nodemap == 0 && len(c.keys) == 1 {
+			n.appendData(bit, c.keys[0], c.values[0])
+			return
+		}
 	}
+	n.appendNode(bit, child)
+}
+
+// orNil returns n, or nil if n holds no entries.
+func (n *bitmapIndexedNode[K, V]) orNil() node[K, V] {
+	if n.datamap == 0 && n.nodemap == 0 {
+		return nil
+	}
+	return n
 }
 
 // collisionNode handles hash collisions
-type collisionNode[K comparable, V any] struct {
+type collisionNode[K any, V any] struct {
 	keys   []K
 	values []V
+	edit   *editToken
 }
 
-func (n *collisionNode[K, V]) get(key K, hash uint64, shift uint) (V, bool) {
-	for i, k := range n.keys {
-		if k == key {
-			return n.values[i], true
-		}
+// updateEntry applies fn to the entry of key, found at i or absent if i is negative,
+// and returns the new node and the change in size.
+func (n *collisionNode[K, V]) updateEntry(i int, key K, fn updateFunc[V]) (node[K, V], int) {
+	var old V
+	if i >= 0 {
+		old = n.values[i]
+	}
+
+	value, action := fn(old, i >= 0)
+	switch {
+	case action == UpdateSet && i >= 0:
+		return n.withValue(i, value), 0
+	case action == UpdateSet:
+		return n.withEntry(key, value), 1
+	case action == UpdateDelete && i >= 0:
+		return n.without(i), -1
+	default:
+		return n, 0
 	}
-	var zero V
-	return zero, false
 }
 
-func (n *collisionNode[K, V]) set(key K, value V, hash uint64, shift uint, _ func(key K) uint64) (node[K, V], bool) {
-	for i, k := range n.keys {
-		if k == key {
-			// Update existing
-			newValues := make([]V, len(n.values))
-			copy(newValues, n.values)
-			newValues[i] = value
+// withValue returns a copy of n with the value of the entry at i replaced.
+func (n *collisionNode[K, V]) withValue(i int, value V) *collisionNode[K, V] {
+	newValues := make([]V, len(n.values))
+	copy(newValues, n.values)
+	newValues[i] = value
 
-			return &collisionNode[K, V]{
-				keys:   n.keys,
-				values: newValues,
-			}, false
-		}
+	return &collisionNode[K, V]{
+		keys:   n.keys,
+		values: newValues,
 	}
+}
 
-	// Add new entry at the end
+// withEntry returns a copy of n with an entry added at the end.
+func (n *collisionNode[K, V]) withEntry(key K, value V) *collisionNode[K, V] {
 	newKeys := make([]K, len(n.keys)+1)
 	newValues := make([]V, len(n.values)+1)
 	copy(newKeys, n.keys)
@@ -322,55 +451,115 @@ func (n *collisionNode[K, V]) set(key K, value V, hash uint64, shift uint, _ fun
 	return &collisionNode[K, V]{
 		keys:   newKeys,
 		values: newValues,
-	}, true
-}
-
-func (n *collisionNode[K, V]) del(key K, hash uint64, shift uint) (node[K, V], bool) {
-	for i, k := range n.keys {
-		if k == key {
-			if len(n.keys) == 2 {
-				// Convert to bitmapIndexedNode when only one entry remains.
-				// The parent bitmapIndexedNode will detect this single-entry node
-				// and collapse it into its own data array.
-				// We use an arbitrary bit position (0) since this node will be collapsed anyway.
-				return &bitmapIndexedNode[K, V]{
-					datamap: 1, // Set first bit to indicate one data entry
-					keys:    []K{n.keys[1-i]},
-					values:  []V{n.values[1-i]},
-				}, true
-			}
-
-			return &collisionNode[K, V]{
-				keys:   removeAt(n.keys, i),
-				values: removeAt(n.values, i),
-			}, true
-		}
 	}
-	return n, false
 }
 
-func (n *collisionNode[K, V]) all() iter.Seq2[K, V] {
-	return func(yield func(K, V) bool) {
-		for i := 0; i < len(n.keys); i++ {
-			if !yield(n.keys[i], n.values[i]) {
-				return
-			}
+// without returns a copy of n without the entry at i.
+func (n *collisionNode[K, V]) without(i int) node[K, V] {
+	if len(n.keys) == 2 {
+		// Convert to bitmapIndexedNode when only one entry remains.
+		// The parent bitmapIndexedNode will detect this single-entry node
+		// and collapse it into its own data array.
+		// We use an arbitrary bit position (0) since this node will be collapsed anyway.
+		return &bitmapIndexedNode[K, V]{
+			datamap: 1, // Set first bit to indicate one data entry
+			keys:    []K{n.keys[1-i]},
+			values:  []V{n.values[1-i]},
 		}
 	}
+
+	return &collisionNode[K, V]{
+		keys:   removeAt(n.keys, i),
+		values: removeAt(n.values, i),
+	}
 }
 
-func (n *collisionNode[K, V]) keysSeq() iter.Seq[K] {
-	return slices.Values(n.keys)
+// newCollisionNode returns a node holding the given entries with the same hash.
+// A single entry is returned as a bitmapIndexedNode, which the parent collapses.
+func newCollisionNode[K any, V any](keys []K, values []V) node[K, V] {
+	switch len(keys) {
+	case 0:
+		return nil
+	case 1:
+		return &bitmapIndexedNode[K, V]{
+			datamap: 1,
+			keys:    keys,
+			values:  values,
+		}
+	}
+	return &collisionNode[K, V]{
+		keys:   keys,
+		values: values,
+	}
 }
 
-func (n *collisionNode[K, V]) valuesSeq() iter.Seq[V] {
-	return slices.Values(n.values)
+func (n *collisionNode[K, V]) entries() ([]K, []V) {
+	return n.keys, n.values
 }
 
 func (n *collisionNode[K, V]) String() string {
 	return collisionNodeString(n, 0)
 }
 
+// countNode returns the number of entries in the subtree rooted at n.
+func countNode[K comparable, V any](n node[K, V]) int {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		count := len(n.keys)
+		for _, child := range n.nodes {
+			count += countNode(child)
+		}
+		return count
+	case *collisionNode[K, V]:
+		return len(n.keys)
+	}
+	return 0
+}
+
+// filterNode returns the subtree of n holding only the entries for which keep returns true,
+// and the number of entries removed. n itself is returned if no entry is removed.
+func filterNode[K comparable, V any](n node[K, V], keep func(key K, value V) bool) (node[K, V], int) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		result := &bitmapIndexedNode[K, V]{}
+		removed := 0
+		for bits := n.datamap | n.nodemap; bits != 0; bits &= bits - 1 {
+			bit := bits & -bits
+			if n.datamap&bit != 0 {
+				idx := popcount(n.datamap & (bit - 1))
+				if keep(n.keys[idx], n.values[idx]) {
+					result.appendData(bit, n.keys[idx], n.values[idx])
+				} else {
+					removed++
+				}
+				continue
+			}
+
+			child, r := filterNode(n.nodes[popcount(n.nodemap&(bit-1))], keep)
+			result.appendChild(bit, child)
+			removed += r
+		}
+		if removed == 0 {
+			return n, 0
+		}
+		return result.orNil(), removed
+	case *collisionNode[K, V]:
+		var keys []K
+		var values []V
+		for i, k := range n.keys {
+			if keep(k, n.values[i]) {
+				keys = append(keys, k)
+				values = append(values, n.values[i])
+			}
+		}
+		if len(keys) == len(n.keys) {
+			return n, 0
+		}
+		return newCollisionNode(keys, values), len(n.keys) - len(keys)
+	}
+	return n, 0
+}
+
 // popcount returns the number of set bits in x.
 func popcount(x uint32) int {
 	x = x - ((x >> 1) & 0x55555555)
@@ -0,0 +1,313 @@
+package champ
+
+import "slices"
+
+// setNode is a node of the trie of a Set, which holds keys without values.
+// Its node types mirror those of Map with the values arrays left out.
+type setNode[K comparable] interface {
+	contains(key K, hash uint64, shift uint) bool
+	add(key K, hash uint64, shift uint, hashFunc func(key K) uint64) (setNode[K], bool)
+	remove(key K, hash uint64, shift uint) (setNode[K], bool)
+}
+
+// bitmapIndexedSetNode is a bitmapIndexedNode holding keys only.
+type bitmapIndexedSetNode[K comparable] struct {
+	nodemap uint32       // Bitmap for child nodes
+	datamap uint32       // Bitmap for keys
+	nodes   []setNode[K] // Array of child nodes (compressed)
+	keys    []K          // Array of keys (compressed)
+}
+
+func (n *bitmapIndexedSetNode[K]) contains(key K, hash uint64, shift uint) bool {
+	bit := uint32(1 << ((hash >> shift) & bitMask))
+
+	if n.datamap&bit != 0 {
+		return n.keys[popcount(n.datamap&(bit-1))] == key
+	}
+	if n.nodemap&bit != 0 {
+		return n.nodes[popcount(n.nodemap&(bit-1))].contains(key, hash, shift+bitsPerLevel)
+	}
+	return false
+}
+
+func (n *bitmapIndexedSetNode[K]) add(key K, hash uint64, shift uint, hashFunc func(key K) uint64) (setNode[K], bool) {
+	bit := uint32(1 << ((hash >> shift) & bitMask))
+
+	if n.datamap&bit != 0 {
+		idx := popcount(n.datamap & (bit - 1))
+		if n.keys[idx] == key {
+			return n, false
+		}
+
+		// Collision
+		subNode := newSetSubNode(n.keys[idx], hashFunc(n.keys[idx]), key, hash, shift+bitsPerLevel)
+		return &bitmapIndexedSetNode[K]{
+			nodemap: n.nodemap | bit,
+			datamap: n.datamap &^ bit,
+			nodes:   insertAt(n.nodes, popcount(n.nodemap&(bit-1)), subNode),
+			keys:    removeAt(n.keys, idx),
+		}, true
+	}
+
+	if n.nodemap&bit != 0 {
+		idx := popcount(n.nodemap & (bit - 1))
+		newNode, added := n.nodes[idx].add(key, hash, shift+bitsPerLevel, hashFunc)
+		if !added {
+			return n, false
+		}
+
+		newNodes := make([]setNode[K], len(n.nodes))
+		copy(newNodes, n.nodes)
+		newNodes[idx] = newNode
+
+		return &bitmapIndexedSetNode[K]{
+			nodemap: n.nodemap,
+			datamap: n.datamap,
+			nodes:   newNodes,
+			keys:    n.keys,
+		}, true
+	}
+
+	// Empty position
+	return &bitmapIndexedSetNode[K]{
+		nodemap: n.nodemap,
+		datamap: n.datamap | bit,
+		nodes:   n.nodes,
+		keys:    insertAt(n.keys, popcount(n.datamap&(bit-1)), key),
+	}, true
+}
+
+func (n *bitmapIndexedSetNode[K]) remove(key K, hash uint64, shift uint) (setNode[K], bool) {
+	bit := uint32(1 << ((hash >> shift) & bitMask))
+
+	if n.datamap&bit != 0 {
+		idx := popcount(n.datamap & (bit - 1))
+		if n.keys[idx] != key {
+			return n, false
+		}
+
+		if len(n.keys) == 1 && len(n.nodes) == 0 {
+			return nil, true
+		}
+
+		return &bitmapIndexedSetNode[K]{
+			nodemap: n.nodemap,
+			datamap: n.datamap &^ bit,
+			nodes:   n.nodes,
+			keys:    removeAt(n.keys, idx),
+		}, true
+	}
+
+	if n.nodemap&bit != 0 {
+		newNode, removed := n.nodes[popcount(n.nodemap&(bit-1))].remove(key, hash, shift+bitsPerLevel)
+		if !removed {
+			return n, false
+		}
+		return n.withChild(bit, newNode), true
+	}
+
+	return n, false
+}
+
+// withChild returns a copy of n with the sub-node at bit replaced by child.
+// A nil child is removed, and a child holding a single key is inlined as data.
+// nil is returned if no key remains.
+func (n *bitmapIndexedSetNode[K]) withChild(bit uint32, child setNode[K]) setNode[K] {
+	idx := popcount(n.nodemap & (bit - 1))
+
+	if child == nil {
+		// Remove empty node
+		if len(n.nodes) == 1 && len(n.keys) == 0 {
+			return nil
+		}
+
+		return &bitmapIndexedSetNode[K]{
+			nodemap: n.nodemap &^ bit,
+			datamap: n.datamap,
+			nodes:   removeAt(n.nodes, idx),
+			keys:    n.keys,
+		}
+	}
+
+	if m, ok := child.(*bitmapIndexedSetNode[K]); ok && m.nodemap == 0 && len(m.keys) == 1 {
+		// Collapse single key node
+		return &bitmapIndexedSetNode[K]{
+			nodemap: n.nodemap &^ bit,
+			datamap: n.datamap | bit,
+			nodes:   removeAt(n.nodes, idx),
+			keys:    insertAt(n.keys, popcount(n.datamap&(bit-1)), m.keys[0]),
+		}
+	}
+
+	newNodes := make([]setNode[K], len(n.nodes))
+	copy(newNodes, n.nodes)
+	newNodes[idx] = child
+
+	return &bitmapIndexedSetNode[K]{
+		nodemap: n.nodemap,
+		datamap: n.datamap,
+		nodes:   newNodes,
+		keys:    n.keys,
+	}
+}
+
+// appendKey adds a key at bit, which must be higher than any bit already set.
+func (n *bitmapIndexedSetNode[K]) appendKey(bit uint32, key K) {
+	n.datamap |= bit
+	n.keys = append(n.keys, key)
+}
+
+// appendNode adds a sub-node at bit, which must be higher than any bit already set.
+func (n *bitmapIndexedSetNode[K]) appendNode(bit uint32, child setNode[K]) {
+	n.nodemap |= bit
+	n.nodes = append(n.nodes, child)
+}
+
+// appendChild adds a sub-node at bit, which must be higher than any bit already set.
+// A nil child is dropped, and a child holding a single key is inlined as data.
+func (n *bitmapIndexedSetNode[K]) appendChild(bit uint32, child setNode[K]) {
+	switch c := child.(type) {
+	case nil:
+		return
+	case *bitmapIndexedSetNode[K]:
+		if c.nodemap == 0 && len(c.keys) == 1 {
+			n.appendKey(bit, c.keys[0])
+			return
+		}
+	}
+	n.appendNode(bit, child)
+}
+
+// orNil returns n, or nil if n holds no keys.
+func (n *bitmapIndexedSetNode[K]) orNil() setNode[K] {
+	if n.datamap == 0 && n.nodemap == 0 {
+		return nil
+	}
+	return n
+}
+
+// newSetSubNode returns the subtree at shift holding two distinct keys.
+func newSetSubNode[K comparable](key1 K, hash1 uint64, key2 K, hash2 uint64, shift uint) setNode[K] {
+	if shift >= maxDepth*bitsPerLevel {
+		return &collisionSetNode[K]{keys: []K{key1, key2}}
+	}
+
+	bit1 := uint32(1 << ((hash1 >> shift) & bitMask))
+	bit2 := uint32(1 << ((hash2 >> shift) & bitMask))
+
+	switch {
+	case bit1 == bit2:
+		// Same position at this level, recurse
+		return &bitmapIndexedSetNode[K]{
+			nodemap: bit1,
+			nodes:   []setNode[K]{newSetSubNode(key1, hash1, key2, hash2, shift+bitsPerLevel)},
+		}
+	case bit1 < bit2:
+		return &bitmapIndexedSetNode[K]{datamap: bit1 | bit2, keys: []K{key1, key2}}
+	default:
+		return &bitmapIndexedSetNode[K]{datamap: bit1 | bit2, keys: []K{key2, key1}}
+	}
+}
+
+// collisionSetNode is a collisionNode holding keys only.
+type collisionSetNode[K comparable] struct {
+	keys []K
+}
+
+func (n *collisionSetNode[K]) contains(key K, hash uint64, shift uint) bool {
+	return slices.Contains(n.keys, key)
+}
+
+func (n *collisionSetNode[K]) add(key K, hash uint64, shift uint, _ func(key K) uint64) (setNode[K], bool) {
+	if slices.Contains(n.keys, key) {
+		return n, false
+	}
+
+	// Add new key at the end
+	newKeys := make([]K, len(n.keys)+1)
+	copy(newKeys, n.keys)
+	newKeys[len(n.keys)] = key
+	return &collisionSetNode[K]{keys: newKeys}, true
+}
+
+func (n *collisionSetNode[K]) remove(key K, hash uint64, shift uint) (setNode[K], bool) {
+	i := slices.Index(n.keys, key)
+	if i < 0 {
+		return n, false
+	}
+	return newCollisionSetNode(removeAt(n.keys, i)), true
+}
+
+// newCollisionSetNode returns a node holding the given keys with the same hash.
+// A single key is returned as a bitmapIndexedSetNode, which the parent collapses.
+func newCollisionSetNode[K comparable](keys []K) setNode[K] {
+	switch len(keys) {
+	case 0:
+		return nil
+	case 1:
+		return &bitmapIndexedSetNode[K]{datamap: 1, keys: keys}
+	}
+	return &collisionSetNode[K]{keys: keys}
+}
+
+// newSetNode returns a trie of keys with the layout of the map trie n, sharing its keys arrays.
+func newSetNode[K comparable, V any](n node[K, V]) setNode[K] {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		s := &bitmapIndexedSetNode[K]{
+			nodemap: n.nodemap,
+			datamap: n.datamap,
+			keys:    n.keys,
+		}
+		if len(n.nodes) > 0 {
+			s.nodes = make([]setNode[K], len(n.nodes))
+			for i, child := range n.nodes {
+				s.nodes[i] = newSetNode(child)
+			}
+		}
+		return s
+	case *collisionNode[K, V]:
+		return &collisionSetNode[K]{keys: n.keys}
+	}
+	return nil
+}
+
+// countSetNode returns the number of keys in the subtree rooted at n.
+func countSetNode[K comparable](n setNode[K]) int {
+	switch n := n.(type) {
+	case *bitmapIndexedSetNode[K]:
+		count := len(n.keys)
+		for _, child := range n.nodes {
+			count += countSetNode(child)
+		}
+		return count
+	case *collisionSetNode[K]:
+		return len(n.keys)
+	}
+	return 0
+}
+
+// allSetNode yields the keys of the subtree n in iteration order,
+// and returns false if the iteration should stop.
+func allSetNode[K comparable](n setNode[K], yield func(K) bool) bool {
+	switch n := n.(type) {
+	case *bitmapIndexedSetNode[K]:
+		for _, k := range n.keys {
+			if !yield(k) {
+				return false
+			}
+		}
+		for _, child := range n.nodes {
+			if !allSetNode(child, yield) {
+				return false
+			}
+		}
+	case *collisionSetNode[K]:
+		for _, k := range n.keys {
+			if !yield(k) {
+				return false
+			}
+		}
+	}
+	return true
+}
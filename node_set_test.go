@@ -0,0 +1,158 @@
+package champ
+
+import "testing"
+
+func TestBitmapIndexedSetNode(t *testing.T) {
+	t.Run("add", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			node          *bitmapIndexedSetNode[string]
+			key           string
+			hash          uint64
+			expected      setNode[string]
+			expectedAdded bool
+		}{
+			{
+				name:          "empty node",
+				node:          &bitmapIndexedSetNode[string]{},
+				key:           "00001",
+				hash:          0b00001,
+				expected:      &bitmapIndexedSetNode[string]{datamap: 0b00010, keys: []string{"00001"}},
+				expectedAdded: true,
+			},
+			{
+				name:          "existing key",
+				node:          &bitmapIndexedSetNode[string]{datamap: 0b00010, keys: []string{"00001"}},
+				key:           "00001",
+				hash:          0b00001,
+				expected:      &bitmapIndexedSetNode[string]{datamap: 0b00010, keys: []string{"00001"}},
+				expectedAdded: false,
+			},
+			{
+				name: "keys in order",
+				node: &bitmapIndexedSetNode[string]{datamap: 0b10001, keys: []string{"00000", "00100"}},
+				key:  "00010",
+				hash: 0b00010,
+				expected: &bitmapIndexedSetNode[string]{
+					datamap: 0b10101,
+					keys:    []string{"00000", "00010", "00100"},
+				},
+				expectedAdded: true,
+			},
+			{
+				name: "same position creates sub-node",
+				node: &bitmapIndexedSetNode[string]{datamap: 0b00010, keys: []string{"00001"}},
+				key:  "100001",
+				hash: 0b100001,
+				expected: &bitmapIndexedSetNode[string]{
+					nodemap: 0b00010,
+					nodes: []setNode[string]{
+						&bitmapIndexedSetNode[string]{datamap: 0b00011, keys: []string{"00001", "100001"}},
+					},
+				},
+				expectedAdded: true,
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				actual, added := tt.node.add(tt.key, tt.hash, 0, testHashFunc)
+				if added != tt.expectedAdded {
+					t.Errorf("add() added expected %v, actual %v", tt.expectedAdded, added)
+				}
+				if !equalSetNode(actual, tt.expected) {
+					t.Errorf("add() expected %+v, actual %+v", tt.expected, actual)
+				}
+				if !added && actual != setNode[string](tt.node) {
+					t.Error("add() of an existing key expected the node itself")
+				}
+			})
+		}
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		for _, tt := range []struct {
+			name            string
+			node            *bitmapIndexedSetNode[string]
+			key             string
+			hash            uint64
+			expected        setNode[string]
+			expectedRemoved bool
+		}{
+			{
+				name:            "missing key",
+				node:            &bitmapIndexedSetNode[string]{datamap: 0b00010, keys: []string{"00001"}},
+				key:             "00010",
+				hash:            0b00010,
+				expected:        &bitmapIndexedSetNode[string]{datamap: 0b00010, keys: []string{"00001"}},
+				expectedRemoved: false,
+			},
+			{
+				name:            "last key",
+				node:            &bitmapIndexedSetNode[string]{datamap: 0b00010, keys: []string{"00001"}},
+				key:             "00001",
+				hash:            0b00001,
+				expected:        nil,
+				expectedRemoved: true,
+			},
+			{
+				name: "sub-node collapses into data",
+				node: &bitmapIndexedSetNode[string]{
+					nodemap: 0b00010,
+					nodes: []setNode[string]{
+						&bitmapIndexedSetNode[string]{datamap: 0b00011, keys: []string{"00001", "100001"}},
+					},
+				},
+				key:             "100001",
+				hash:            0b100001,
+				expected:        &bitmapIndexedSetNode[string]{datamap: 0b00010, keys: []string{"00001"}},
+				expectedRemoved: true,
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				actual, removed := tt.node.remove(tt.key, tt.hash, 0)
+				if removed != tt.expectedRemoved {
+					t.Errorf("remove() removed expected %v, actual %v", tt.expectedRemoved, removed)
+				}
+				if !equalSetNode(actual, tt.expected) {
+					t.Errorf("remove() expected %+v, actual %+v", tt.expected, actual)
+				}
+			})
+		}
+	})
+}
+
+func TestCollisionSetNode(t *testing.T) {
+	n := &collisionSetNode[string]{keys: []string{"a", "b"}}
+
+	added, ok := n.add("c", 0, 0, nil)
+	if !ok || !equalSetNode(added, &collisionSetNode[string]{keys: []string{"a", "b", "c"}}) {
+		t.Errorf("add() expected a collision node with 3 keys, actual %+v", added)
+	}
+	if actual, ok := n.add("a", 0, 0, nil); ok || actual != setNode[string](n) {
+		t.Error("add() of an existing key expected the node itself")
+	}
+
+	// a single remaining key is returned as a bitmapIndexedSetNode, which the parent collapses
+	removed, ok := n.remove("a", 0, 0)
+	if !ok || !equalSetNode(removed, &bitmapIndexedSetNode[string]{datamap: 1, keys: []string{"b"}}) {
+		t.Errorf("remove() expected a single key node, actual %+v", removed)
+	}
+	if !n.contains("b", 0, 0) || n.contains("c", 0, 0) {
+		t.Error("contains() of the original node changed after add and remove")
+	}
+}
+
+func TestNewSetNode(t *testing.T) {
+	m := NewWithHasher[string, int](testHashFunc)
+	for _, k := range []string{"00001", "100001", "00010", "1111111111111111111111111111111111111111111111111111111111111111"} {
+		m = m.Set(k, 0)
+	}
+
+	s := newSetNode(m.root)
+	var expected setNode[string] = &bitmapIndexedSetNode[string]{}
+	for k := range m.Keys() {
+		expected, _ = expected.add(k, testHashFunc(k), 0, testHashFunc)
+	}
+	if !equalSetNode(s, expected) {
+		t.Errorf("newSetNode() expected the layout of the map %+v, actual %+v", expected, s)
+	}
+}
@@ -94,12 +94,12 @@ func TestBitmapIndexedNode(t *testing.T) {
 			},
 		} {
 			t.Run(tt.name, func(t *testing.T) {
-				got, ok := tt.node.get(tt.key, tt.hash, tt.shift)
+				got, ok := nodeGet[string, int](tt.node, tt.key, tt.hash, tt.shift)
 				if ok != tt.expectedOk {
-					t.Errorf("get() ok = %v, expected %v", ok, tt.expectedOk)
+					t.Errorf("nodeGet() ok = %v, expected %v", ok, tt.expectedOk)
 				}
 				if got != tt.expected {
-					t.Errorf("get() value = %d, expected %d", got, tt.expected)
+					t.Errorf("nodeGet() value = %d, expected %d", got, tt.expected)
 				}
 			})
 		}
@@ -203,12 +203,12 @@ func TestBitmapIndexedNode(t *testing.T) {
 			},
 		} {
 			t.Run(tt.name, func(t *testing.T) {
-				result, added := tt.node.set(tt.key, tt.value, tt.hash, tt.shift, testHashFunc)
+				result, added := nodeSet[string, int](tt.node, tt.key, tt.value, tt.hash, tt.shift, testHashFunc)
 				if added != tt.expectedAdded {
-					t.Errorf("set() added = %v, expected %v", added, tt.expectedAdded)
+					t.Errorf("nodeSet() added = %v, expected %v", added, tt.expectedAdded)
 				}
 				if !equalNode(result, tt.expected) {
-					t.Errorf("set() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
+					t.Errorf("nodeSet() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
 				}
 			})
 		}
@@ -327,12 +327,12 @@ func TestBitmapIndexedNode(t *testing.T) {
 			},
 		} {
 			t.Run(tt.name, func(t *testing.T) {
-				result, deleted := tt.node.del(tt.key, tt.hash, tt.shift)
+				result, deleted := nodeDel[string, int](tt.node, tt.key, tt.hash, tt.shift)
 				if deleted != tt.expectedDeleted {
-					t.Errorf("del() deleted = %v, expected %v", deleted, tt.expectedDeleted)
+					t.Errorf("nodeDel() deleted = %v, expected %v", deleted, tt.expectedDeleted)
 				}
 				if !equalNode(result, tt.expected) {
-					t.Errorf("set() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
+					t.Errorf("nodeSet() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
 				}
 			})
 		}
@@ -390,12 +390,12 @@ func TestCollisionNode(t *testing.T) {
 			},
 		} {
 			t.Run(tt.name, func(t *testing.T) {
-				got, ok := tt.node.get(tt.key, 0, 0) // hash and shift not used
+				got, ok := nodeGet[string, int](tt.node, tt.key, 0, 0) // hash and shift not used
 				if ok != tt.expectedOk {
-					t.Errorf("get() found = %v, expected %v", ok, tt.expectedOk)
+					t.Errorf("nodeGet() found = %v, expected %v", ok, tt.expectedOk)
 				}
 				if got != tt.expected {
-					t.Errorf("get() value = %d, expected %d", got, tt.expected)
+					t.Errorf("nodeGet() value = %d, expected %d", got, tt.expected)
 				}
 			})
 		}
@@ -440,12 +440,12 @@ func TestCollisionNode(t *testing.T) {
 			},
 		} {
 			t.Run(tt.name, func(t *testing.T) {
-				result, added := tt.node.set(tt.key, tt.value, 0, 0, testHashFunc) // hash and shift not used
+				result, added := nodeSet[string, int](tt.node, tt.key, tt.value, 0, 0, testHashFunc) // hash and shift not used
 				if added != tt.expectedAdded {
-					t.Errorf("set() added = %v, expected %v", added, tt.expectedAdded)
+					t.Errorf("nodeSet() added = %v, expected %v", added, tt.expectedAdded)
 				}
 				if !equalNode(result, tt.expected) {
-					t.Errorf("set() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
+					t.Errorf("nodeSet() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
 				}
 			})
 		}
@@ -501,12 +501,12 @@ func TestCollisionNode(t *testing.T) {
 			},
 		} {
 			t.Run(tt.name, func(t *testing.T) {
-				result, deleted := tt.node.del(tt.key, 0, 0) // hash and shift not used
+				result, deleted := nodeDel[string, int](tt.node, tt.key, 0, 0) // hash and shift not used
 				if deleted != tt.expectedDeleted {
-					t.Errorf("del() deleted = %v, expected %v", deleted, tt.expectedDeleted)
+					t.Errorf("nodeDel() deleted = %v, expected %v", deleted, tt.expectedDeleted)
 				}
 				if !equalNode(result, tt.expected) {
-					t.Errorf("del() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
+					t.Errorf("nodeDel() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
 				}
 			})
 		}
@@ -0,0 +1,199 @@
+package champ
+
+import "slices"
+
+// editToken identifies the Builder that owns a node.
+// Nodes stamped with the token of a Builder may be mutated in place by that Builder.
+type editToken struct {
+	_ byte // non-zero size so that every token has a distinct address
+}
+
+// editable returns n itself if it is owned by edit, or an owned copy of n otherwise.
+func (n *bitmapIndexedNode[K, V]) editable(edit *editToken) *bitmapIndexedNode[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &bitmapIndexedNode[K, V]{
+		nodemap: n.nodemap,
+		datamap: n.datamap,
+		nodes:   slices.Clone(n.nodes),
+		keys:    slices.Clone(n.keys),
+		values:  slices.Clone(n.values),
+		edit:    edit,
+	}
+}
+
+// editable returns n itself if it is owned by edit, or an owned copy of n otherwise.
+func (n *collisionNode[K, V]) editable(edit *editToken) *collisionNode[K, V] {
+	if edit != nil && n.edit == edit {
+		return n
+	}
+	return &collisionNode[K, V]{
+		keys:   slices.Clone(n.keys),
+		values: slices.Clone(n.values),
+		edit:   edit,
+	}
+}
+
+// nodeSetMut is like nodeSet but mutates the nodes owned by edit in place.
+func nodeSetMut[K comparable, V any](
+	n node[K, V],
+	edit *editToken,
+	key K,
+	value V,
+	hash uint64,
+	shift uint,
+	hashFunc func(key K) uint64,
+) (node[K, V], bool) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		bit := uint32(1 << ((hash >> shift) & bitMask))
+
+		if n.datamap&bit != 0 {
+			idx := popcount(n.datamap & (bit - 1))
+			if n.keys[idx] == key {
+				e := n.editable(edit)
+				e.values[idx] = value
+				return e, false
+			}
+
+			// Collision
+			subNode := n.createSubNode(
+				edit,
+				n.keys[idx], n.values[idx], hashFunc(n.keys[idx]),
+				key, value, hash,
+				shift+bitsPerLevel,
+			)
+			e := n.editable(edit)
+			e.nodemap |= bit
+			e.datamap &^= bit
+			e.nodes = slices.Insert(e.nodes, popcount(e.nodemap&(bit-1)), subNode)
+			e.keys = slices.Delete(e.keys, idx, idx+1)
+			e.values = slices.Delete(e.values, idx, idx+1)
+			return e, true
+		}
+
+		if n.nodemap&bit != 0 {
+			idx := popcount(n.nodemap & (bit - 1))
+			newNode, added := nodeSetMut(n.nodes[idx], edit, key, value, hash, shift+bitsPerLevel, hashFunc)
+			if newNode == n.nodes[idx] {
+				return n, added
+			}
+
+			e := n.editable(edit)
+			e.nodes[idx] = newNode
+			return e, added
+		}
+
+		// Empty position
+		idx := popcount(n.datamap & (bit - 1))
+		e := n.editable(edit)
+		e.datamap |= bit
+		e.keys = slices.Insert(e.keys, idx, key)
+		e.values = slices.Insert(e.values, idx, value)
+		return e, true
+	case *collisionNode[K, V]:
+		for i, k := range n.keys {
+			if k == key {
+				e := n.editable(edit)
+				e.values[i] = value
+				return e, false
+			}
+		}
+
+		e := n.editable(edit)
+		e.keys = append(e.keys, key)
+		e.values = append(e.values, value)
+		return e, true
+	}
+	return n, false
+}
+
+// nodeDelMut is like nodeDel but mutates the nodes owned by edit in place.
+func nodeDelMut[K comparable, V any](n node[K, V], edit *editToken, key K, hash uint64, shift uint) (node[K, V], bool) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		bit := uint32(1 << ((hash >> shift) & bitMask))
+
+		if n.datamap&bit != 0 {
+			idx := popcount(n.datamap & (bit - 1))
+			if n.keys[idx] != key {
+				return n, false
+			}
+
+			if len(n.keys) == 1 && len(n.nodes) == 0 {
+				return nil, true
+			}
+
+			e := n.editable(edit)
+			e.datamap &^= bit
+			e.keys = slices.Delete(e.keys, idx, idx+1)
+			e.values = slices.Delete(e.values, idx, idx+1)
+			return e, true
+		}
+
+		if n.nodemap&bit != 0 {
+			idx := popcount(n.nodemap & (bit - 1))
+			newNode, deleted := nodeDelMut(n.nodes[idx], edit, key, hash, shift+bitsPerLevel)
+
+			if !deleted {
+				return n, false
+			}
+
+			if newNode == nil {
+				// Remove empty node
+				if len(n.nodes) == 1 && len(n.keys) == 0 {
+					return nil, true
+				}
+
+				e := n.editable(edit)
+				e.nodemap &^= bit
+				e.nodes = slices.Delete(e.nodes, idx, idx+1)
+				return e, true
+			}
+
+			if m, ok := newNode.(*bitmapIndexedNode[K, V]); ok && m.nodemap == 0 && len(m.keys) == 1 {
+				// Collapse single entry node
+				dataIdx := popcount(n.datamap & (bit - 1))
+				e := n.editable(edit)
+				e.nodemap &^= bit
+				e.datamap |= bit
+				e.nodes = slices.Delete(e.nodes, idx, idx+1)
+				e.keys = slices.Insert(e.keys, dataIdx, m.keys[0])
+				e.values = slices.Insert(e.values, dataIdx, m.values[0])
+				return e, true
+			}
+
+			if newNode == n.nodes[idx] {
+				return n, true
+			}
+
+			e := n.editable(edit)
+			e.nodes[idx] = newNode
+			return e, true
+		}
+
+		return n, false
+	case *collisionNode[K, V]:
+		for i, k := range n.keys {
+			if k == key {
+				if len(n.keys) == 2 {
+					// Convert to a single entry bitmapIndexedNode, which the parent collapses.
+					return &bitmapIndexedNode[K, V]{
+						datamap: 1,
+						keys:    []K{n.keys[1-i]},
+						values:  []V{n.values[1-i]},
+						edit:    edit,
+					}, true
+				}
+
+				e := n.editable(edit)
+				e.keys = slices.Delete(e.keys, i, i+1)
+				e.values = slices.Delete(e.values, i, i+1)
+				return e, true
+			}
+		}
+		return n, false
+	}
+	return n, false
+}
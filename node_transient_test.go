@@ -0,0 +1,246 @@
+package champ
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBitmapIndexedNodeMut(t *testing.T) {
+	t.Run("owned node is modified in place", func(t *testing.T) {
+		edit := &editToken{}
+		n := &bitmapIndexedNode[string, int]{
+			datamap: 0b00010,
+			keys:    []string{"00001"},
+			values:  []int{100},
+			edit:    edit,
+		}
+
+		result, added := nodeSetMut[string, int](n, edit, "00001", 200, 0b00001, 0, testHashFunc)
+		if added {
+			t.Errorf("nodeSetMut() added = %v, expected %v", added, false)
+		}
+		if result != node[string, int](n) {
+			t.Error("nodeSetMut() copied a node owned by the edit token")
+		}
+		if n.values[0] != 200 {
+			t.Errorf("nodeSetMut() value = %d, expected %d", n.values[0], 200)
+		}
+	})
+
+	t.Run("unowned node is copied", func(t *testing.T) {
+		n := &bitmapIndexedNode[string, int]{
+			datamap: 0b00010,
+			keys:    []string{"00001"},
+			values:  []int{100},
+		}
+
+		result, added := nodeSetMut[string, int](n, &editToken{}, "00011", 200, 0b00011, 0, testHashFunc)
+		if !added {
+			t.Errorf("nodeSetMut() added = %v, expected %v", added, true)
+		}
+		if result == node[string, int](n) {
+			t.Error("nodeSetMut() modified a node not owned by the edit token")
+		}
+		expected := &bitmapIndexedNode[string, int]{
+			datamap: 0b00010,
+			keys:    []string{"00001"},
+			values:  []int{100},
+		}
+		if !equalNode[string, int](n, expected) {
+			t.Errorf("nodeSetMut() modified original node\nactual:\n%s\nexpected:\n%s", n, expected)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			node          *bitmapIndexedNode[string, int]
+			key           string
+			value         int
+			hash          uint64
+			shift         uint
+			expectedAdded bool
+			expected      node[string, int]
+		}{
+			{
+				name:          "set in empty node",
+				node:          &bitmapIndexedNode[string, int]{},
+				key:           "00001",
+				value:         100,
+				hash:          0b00001,
+				shift:         0,
+				expectedAdded: true,
+				expected: &bitmapIndexedNode[string, int]{
+					datamap: 0b00010,
+					keys:    []string{"00001"},
+					values:  []int{100},
+				},
+			},
+			{
+				name: "collision creates child bit indexed node",
+				node: &bitmapIndexedNode[string, int]{
+					datamap: 0b00010,
+					keys:    []string{"00001"},
+					values:  []int{100},
+				},
+				key:           "0000100001",
+				value:         200,
+				hash:          0b0000100001,
+				shift:         0,
+				expectedAdded: true,
+				expected: &bitmapIndexedNode[string, int]{
+					nodemap: 0b00010,
+					nodes: []node[string, int]{
+						&bitmapIndexedNode[string, int]{
+							datamap: 0b00011,
+							keys:    []string{"00001", "0000100001"},
+							values:  []int{100, 200},
+						},
+					},
+				},
+			},
+			{
+				name: "collision creates child collision node",
+				node: &bitmapIndexedNode[string, int]{
+					datamap: 0b00010,
+					keys:    []string{"00001" + strings.Repeat("00000", 11)},
+					values:  []int{100},
+				},
+				key:           "0000000001" + strings.Repeat("00000", 11),
+				value:         200,
+				hash:          0b0000000001 << (5 * 11),
+				shift:         5 * 11,
+				expectedAdded: true,
+				expected: &bitmapIndexedNode[string, int]{
+					nodemap: 0b00010,
+					nodes: []node[string, int]{
+						&bitmapIndexedNode[string, int]{
+							nodemap: 0b00001,
+							nodes: []node[string, int]{
+								&collisionNode[string, int]{
+									keys: []string{
+										"0000000001" + strings.Repeat("00000", 11),
+										"00001" + strings.Repeat("00000", 11),
+									},
+									values: []int{200, 100},
+								},
+							},
+						},
+					},
+				},
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				result, added := nodeSetMut[string, int](tt.node, &editToken{}, tt.key, tt.value, tt.hash, tt.shift, testHashFunc)
+				if added != tt.expectedAdded {
+					t.Errorf("nodeSetMut() added = %v, expected %v", added, tt.expectedAdded)
+				}
+				if !equalNode(result, tt.expected) {
+					t.Errorf("nodeSetMut() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		for _, tt := range []struct {
+			name            string
+			node            *bitmapIndexedNode[string, int]
+			key             string
+			hash            uint64
+			shift           uint
+			expectedDeleted bool
+			expected        node[string, int]
+		}{
+			{
+				name: "delete only key returns nil",
+				node: &bitmapIndexedNode[string, int]{
+					datamap: 0b00010,
+					keys:    []string{"00001"},
+					values:  []int{100},
+				},
+				key:             "00001",
+				hash:            0b00001,
+				shift:           0,
+				expectedDeleted: true,
+				expected:        nil,
+			},
+			{
+				name: "collapse single-entry child bitmap indexed node",
+				node: &bitmapIndexedNode[string, int]{
+					nodemap: 0b00001,
+					nodes: []node[string, int]{
+						&bitmapIndexedNode[string, int]{
+							datamap: 0b00110,
+							keys:    []string{"0000100000", "0000000010"},
+							values:  []int{100, 200},
+						},
+					},
+				},
+				key:             "0000100000",
+				hash:            0b0000100000,
+				shift:           0,
+				expectedDeleted: true,
+				expected: &bitmapIndexedNode[string, int]{
+					datamap: 0b00001,
+					keys:    []string{"0000000010"},
+					values:  []int{200},
+				},
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				result, deleted := nodeDelMut[string, int](tt.node, &editToken{}, tt.key, tt.hash, tt.shift)
+				if deleted != tt.expectedDeleted {
+					t.Errorf("nodeDelMut() deleted = %v, expected %v", deleted, tt.expectedDeleted)
+				}
+				if !equalNode(result, tt.expected) {
+					t.Errorf("nodeDelMut() result node not as expected\nactual:\n%s\nexpected:\n%s", result, tt.expected)
+				}
+			})
+		}
+	})
+}
+
+func TestCollisionNodeMut(t *testing.T) {
+	t.Run("delete from two keys converts to bitmap", func(t *testing.T) {
+		n := &collisionNode[string, int]{
+			keys:   []string{"00001", "00010"},
+			values: []int{100, 200},
+		}
+
+		result, deleted := nodeDelMut[string, int](n, &editToken{}, "00010", 0, 0)
+		if !deleted {
+			t.Errorf("nodeDelMut() deleted = %v, expected %v", deleted, true)
+		}
+		expected := &bitmapIndexedNode[string, int]{
+			datamap: 0b00001,
+			keys:    []string{"00001"},
+			values:  []int{100},
+		}
+		if !equalNode(result, node[string, int](expected)) {
+			t.Errorf("nodeDelMut() result node not as expected\nactual:\n%s\nexpected:\n%s", result, expected)
+		}
+	})
+
+	t.Run("add new key to unowned node", func(t *testing.T) {
+		n := &collisionNode[string, int]{
+			keys:   []string{"00001", "00100"},
+			values: []int{100, 200},
+		}
+
+		result, added := nodeSetMut[string, int](n, &editToken{}, "00010", 300, 0, 0, testHashFunc)
+		if !added {
+			t.Errorf("nodeSetMut() added = %v, expected %v", added, true)
+		}
+		if len(n.keys) != 2 {
+			t.Errorf("nodeSetMut() modified a node not owned by the edit token")
+		}
+		expected := &collisionNode[string, int]{
+			keys:   []string{"00001", "00010", "00100"},
+			values: []int{100, 300, 200},
+		}
+		if !equalNode(result, node[string, int](expected)) {
+			t.Errorf("nodeSetMut() result node not as expected\nactual:\n%s\nexpected:\n%s", result, expected)
+		}
+	})
+}
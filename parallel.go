@@ -0,0 +1,229 @@
+package champ
+
+import (
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ParallelRange calls fn for every entry of the map from multiple goroutines,
+// handing out the subtrees of the root node to at most workers goroutines.
+// A non-positive workers uses runtime.GOMAXPROCS(0).
+//
+// fn must be safe for concurrent use. If fn returns false, the iteration stops
+// as soon as the other goroutines observe it, and ParallelRange returns after all of them have stopped.
+func (m *Map[K, V]) ParallelRange(workers int, fn func(key K, value V) bool) {
+	root, ok := m.root.(*bitmapIndexedNode[K, V])
+	if !ok {
+		return
+	}
+
+	var stopped atomic.Bool
+	rangeSeq := func(seq iter.Seq2[K, V]) {
+		for k, v := range seq {
+			if stopped.Load() {
+				return
+			}
+			if !fn(k, v) {
+				stopped.Store(true)
+				return
+			}
+		}
+	}
+
+	// The first task covers the entries stored in the root itself.
+	parallelDo(workers, len(root.nodes)+1, func(i int) {
+		if i == 0 {
+			rangeSeq(rootEntries(root))
+			return
+		}
+		rangeSeq(allNode(root.nodes[i-1]))
+	})
+}
+
+// ParallelMapValues returns a map with the same keys as m and the values replaced by fn applied to each entry,
+// computed by at most workers goroutines. A non-positive workers uses runtime.GOMAXPROCS(0).
+//
+// The result has the same trie structure as m and shares its hash function and key arrays.
+// fn must be safe for concurrent use.
+func ParallelMapValues[K comparable, V, W any](m *Map[K, V], workers int, fn func(key K, value V) W) *Map[K, W] {
+	root, ok := m.root.(*bitmapIndexedNode[K, V])
+	if !ok {
+		return &Map[K, W]{hasher: m.hasher}
+	}
+
+	result := &bitmapIndexedNode[K, W]{
+		datamap: root.datamap,
+		nodemap: root.nodemap,
+		keys:    root.keys,
+		values:  make([]W, len(root.values)),
+		nodes:   make([]node[K, W], len(root.nodes)),
+	}
+	parallelDo(workers, len(root.nodes)+1, func(i int) {
+		if i == 0 {
+			for j, k := range root.keys {
+				result.values[j] = fn(k, root.values[j])
+			}
+			return
+		}
+		result.nodes[i-1] = mapValuesNode(root.nodes[i-1], fn)
+	})
+
+	return &Map[K, W]{
+		root:   result,
+		size:   m.size,
+		hasher: m.hasher,
+	}
+}
+
+// ParallelFilter returns a map containing only the entries for which keep returns true,
+// filtering the subtrees of the root node with at most workers goroutines.
+// A non-positive workers uses runtime.GOMAXPROCS(0).
+//
+// keep must be safe for concurrent use. If every entry is kept, m itself is returned.
+func (m *Map[K, V]) ParallelFilter(workers int, keep func(key K, value V) bool) *Map[K, V] {
+	root, ok := m.root.(*bitmapIndexedNode[K, V])
+	if !ok {
+		return m
+	}
+
+	children := make([]node[K, V], len(root.nodes))
+	removedChildren := make([]int, len(root.nodes))
+	parallelDo(workers, len(root.nodes), func(i int) {
+		children[i], removedChildren[i] = filterNode(root.nodes[i], keep)
+	})
+
+	result := &bitmapIndexedNode[K, V]{}
+	removed := 0
+	for bits := root.datamap | root.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+		if root.datamap&bit != 0 {
+			idx := popcount(root.datamap & (bit - 1))
+			if keep(root.keys[idx], root.values[idx]) {
+				result.appendData(bit, root.keys[idx], root.values[idx])
+			} else {
+				removed++
+			}
+			continue
+		}
+
+		idx := popcount(root.nodemap & (bit - 1))
+		result.appendChild(bit, children[idx])
+		removed += removedChildren[idx]
+	}
+
+	if removed == 0 {
+		return m
+	}
+	return m.withRoot(result.orNil(), m.size-removed)
+}
+
+// Split divides the entries of the map into at most n iterators over disjoint sets of entries,
+// which can be consumed independently from different goroutines.
+//
+// Each iterator covers consecutive subtrees of the root node, balanced by their number of entries.
+// Since the root has at most 32 positions, fewer iterators are returned for larger n.
+func (m *Map[K, V]) Split(n int) []iter.Seq2[K, V] {
+	root, ok := m.root.(*bitmapIndexedNode[K, V])
+	if !ok {
+		return nil
+	}
+
+	n = max(n, 1)
+	target := (m.size + n - 1) / n
+
+	var parts []iter.Seq2[K, V]
+	part := &bitmapIndexedNode[K, V]{}
+	count := 0
+	for bits := root.datamap | root.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+		if root.datamap&bit != 0 {
+			idx := popcount(root.datamap & (bit - 1))
+			part.appendData(bit, root.keys[idx], root.values[idx])
+			count++
+		} else {
+			child := root.nodes[popcount(root.nodemap&(bit-1))]
+			part.appendNode(bit, child)
+			count += countNode(child)
+		}
+
+		if count >= target && len(parts) < n-1 {
+			parts = append(parts, allNode(part))
+			part = &bitmapIndexedNode[K, V]{}
+			count = 0
+		}
+	}
+	if count > 0 {
+		parts = append(parts, allNode(part))
+	}
+	return parts
+}
+
+// rootEntries returns an iterator over the entries stored in n itself, excluding its sub-nodes.
+func rootEntries[K comparable, V any](n *bitmapIndexedNode[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for i, k := range n.keys {
+			if !yield(k, n.values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// mapValuesNode returns a node with the structure and keys of n and the values replaced by fn.
+func mapValuesNode[K comparable, V, W any](n node[K, V], fn func(key K, value V) W) node[K, W] {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		result := &bitmapIndexedNode[K, W]{
+			datamap: n.datamap,
+			nodemap: n.nodemap,
+			keys:    n.keys,
+			values:  make([]W, len(n.values)),
+		}
+		for i, k := range n.keys {
+			result.values[i] = fn(k, n.values[i])
+		}
+		if len(n.nodes) > 0 {
+			result.nodes = make([]node[K, W], len(n.nodes))
+			for i, child := range n.nodes {
+				result.nodes[i] = mapValuesNode(child, fn)
+			}
+		}
+		return result
+	case *collisionNode[K, V]:
+		result := &collisionNode[K, W]{
+			keys:   n.keys,
+			values: make([]W, len(n.values)),
+		}
+		for i, k := range n.keys {
+			result.values[i] = fn(k, n.values[i])
+		}
+		return result
+	}
+	return nil
+}
+
+// parallelDo calls fn for every i in [0, n) from at most workers goroutines.
+// A non-positive workers uses runtime.GOMAXPROCS(0).
+func parallelDo(workers, n int, fn func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				fn(i)
+			}
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,153 @@
+package champ
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallel(t *testing.T) {
+	// low entropy hash function producing deep tries and collision nodes
+	lowEntropy := func(key string) uint64 { return uint64(len(key)+int(key[len(key)-1])%3) << 58 }
+
+	for _, tt := range []struct {
+		name string
+		m    func() *Map[string, int]
+	}{
+		{
+			name: "empty",
+			m:    New[string, int],
+		},
+		{
+			name: "single entry",
+			m:    func() *Map[string, int] { return New[string, int]().Set("k0", 0) },
+		},
+		{
+			name: "large map",
+			m: func() *Map[string, int] {
+				m := New[string, int]()
+				for i := range 10000 {
+					m = m.Set(fmt.Sprintf("k%d", i), i)
+				}
+				return m
+			},
+		},
+		{
+			name: "low entropy hash function",
+			m: func() *Map[string, int] {
+				m := NewWithHasher[string, int](lowEntropy)
+				for i := range 1000 {
+					m = m.Set(fmt.Sprintf("k%d", i), i)
+				}
+				return m
+			},
+		},
+	} {
+		for _, workers := range []int{0, 1, 4} {
+			t.Run(fmt.Sprintf("%s/workers %d", tt.name, workers), func(t *testing.T) {
+				m := tt.m()
+
+				t.Run("ParallelRange", func(t *testing.T) {
+					var mu sync.Mutex
+					seen := map[string]int{}
+					m.ParallelRange(workers, func(k string, v int) bool {
+						mu.Lock()
+						defer mu.Unlock()
+						seen[k]++
+						return true
+					})
+					if len(seen) != m.Len() {
+						t.Errorf("ParallelRange() visited %d keys, expected %d", len(seen), m.Len())
+					}
+					for k, n := range seen {
+						if n != 1 {
+							t.Errorf("ParallelRange() visited %q %d times", k, n)
+						}
+					}
+				})
+
+				t.Run("ParallelMapValues", func(t *testing.T) {
+					actual := ParallelMapValues(m, workers, func(k string, v int) string { return fmt.Sprintf("%s=%d", k, v) })
+					expected := &Map[string, string]{hasher: m.hasher}
+					for k, v := range m.All() {
+						expected = expected.Set(k, fmt.Sprintf("%s=%d", k, v))
+					}
+					if actual.Len() != expected.Len() {
+						t.Errorf("ParallelMapValues() Len() expected %d, actual %d", expected.Len(), actual.Len())
+					}
+					if !sameHasher(actual, expected) || !equalNode(actual.root, expected.root) {
+						t.Error("ParallelMapValues() result does not match expected")
+					}
+				})
+
+				t.Run("ParallelFilter", func(t *testing.T) {
+					keep := func(k string, v int) bool { return v%3 == 0 }
+					actual := m.ParallelFilter(workers, keep)
+					expected := m.empty()
+					for k, v := range m.All() {
+						if keep(k, v) {
+							expected = expected.Set(k, v)
+						}
+					}
+					if actual.Len() != expected.Len() {
+						t.Errorf("ParallelFilter() Len() expected %d, actual %d", expected.Len(), actual.Len())
+					}
+					// structural comparison also verifies the canonical form
+					if !equalNode(actual.root, expected.root) {
+						t.Error("ParallelFilter() result does not match expected")
+					}
+					if all := m.ParallelFilter(workers, func(string, int) bool { return true }); all != m {
+						t.Error("ParallelFilter() keeping every entry returned a new map")
+					}
+				})
+			})
+		}
+
+		for _, n := range []int{0, 1, 3, 8, 100} {
+			t.Run(fmt.Sprintf("%s/Split(%d)", tt.name, n), func(t *testing.T) {
+				m := tt.m()
+				parts := m.Split(n)
+				if len(parts) > max(n, 1) {
+					t.Errorf("Split(%d) returned %d parts", n, len(parts))
+				}
+
+				seen := map[string]int{}
+				for _, part := range parts {
+					count := 0
+					for k := range part {
+						seen[k]++
+						count++
+					}
+					if count == 0 {
+						t.Errorf("Split(%d) returned an empty part", n)
+					}
+				}
+				if len(seen) != m.Len() {
+					t.Errorf("Split(%d) parts cover %d keys, expected %d", n, len(seen), m.Len())
+				}
+				for k, c := range seen {
+					if c != 1 {
+						t.Errorf("Split(%d) yielded %q %d times", n, k, c)
+					}
+				}
+			})
+		}
+	}
+
+	t.Run("ParallelRange stops early", func(t *testing.T) {
+		m := New[int, int]()
+		for i := range 10000 {
+			m = m.Set(i, i)
+		}
+
+		var calls atomic.Int64
+		m.ParallelRange(4, func(int, int) bool {
+			return calls.Add(1) < 10
+		})
+		// every worker may have made one more call before observing the stop
+		if calls.Load() > 10+4 {
+			t.Errorf("ParallelRange() called fn %d times after stopping", calls.Load())
+		}
+	})
+}
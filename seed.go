@@ -0,0 +1,146 @@
+package champ
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+)
+
+// Seed is a seed for a deterministic hash function.
+//
+// Unlike the default hash function, which is seeded randomly per process,
+// maps created with the same Seed hash keys identically across processes.
+// Their trie shape and iteration order only depend on their contents and history.
+type Seed uint64
+
+// SeedFromBytes derives a Seed from arbitrary bytes.
+func SeedFromBytes(b []byte) Seed {
+	return Seed(hashBytes(0, b))
+}
+
+// NewWithSeed creates a new empty CHAMP map which hashes keys deterministically with the given seed.
+//
+// Strings, numbers, booleans, and arrays and structs composed of them hash identically across processes.
+// Pointers, channels and interfaces holding them hash by address, which is only stable within a process.
+// Maps created with equal seeds share the hash function.
+func NewWithSeed[K comparable, V any](seed Seed) *Map[K, V] {
+	return &Map[K, V]{
+		hasher: &hasher[K]{
+			fn:     func(key K) uint64 { return hashSeeded(seed, key) },
+			seed:   seed,
+			seeded: true,
+		},
+	}
+}
+
+// NewSetWithSeed creates a new empty set which hashes elements deterministically with the given seed.
+func NewSetWithSeed[K comparable](seed Seed) *Set[K] {
+	return &Set[K]{hasher: NewWithSeed[K, struct{}](seed).hasher}
+}
+
+const (
+	seedMultiplier = 0x9e3779b97f4a7c15
+	seedPrime      = 0xff51afd7ed558ccd
+)
+
+// hashSeeded returns the deterministic hash of key.
+func hashSeeded[K comparable](seed Seed, key K) uint64 {
+	var h uint64
+	switch k := any(key).(type) {
+	case string:
+		h = hashBytes(uint64(seed), k)
+	case int:
+		h = mixWord(uint64(seed), uint64(k))
+	case int64:
+		h = mixWord(uint64(seed), uint64(k))
+	case int32:
+		h = mixWord(uint64(seed), uint64(k))
+	case uint:
+		h = mixWord(uint64(seed), uint64(k))
+	case uint64:
+		h = mixWord(uint64(seed), k)
+	case uint32:
+		h = mixWord(uint64(seed), uint64(k))
+	default:
+		h = hashValue(uint64(seed), reflect.ValueOf(&key).Elem())
+	}
+	return finalize(h)
+}
+
+// hashValue mixes the content of v into h.
+func hashValue(h uint64, v reflect.Value) uint64 {
+	switch v.Kind() {
+	case reflect.String:
+		return hashBytes(h, v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			return mixWord(h, 1)
+		}
+		return mixWord(h, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return mixWord(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return mixWord(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return mixWord(h, floatBits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return mixWord(mixWord(h, floatBits(real(c))), floatBits(imag(c)))
+	case reflect.Array:
+		for i := range v.Len() {
+			h = hashValue(h, v.Index(i))
+		}
+		return h
+	case reflect.Struct:
+		for i := range v.NumField() {
+			h = hashValue(h, v.Field(i))
+		}
+		return h
+	case reflect.Interface:
+		if v.IsNil() {
+			return mixWord(h, 0)
+		}
+		e := v.Elem()
+		return hashValue(hashBytes(h, e.Type().String()), e)
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return mixWord(h, uint64(v.Pointer()))
+	default:
+		// The remaining kinds are not comparable, so only an interface holding them can reach here.
+		panic("champ: unhashable key type " + v.Type().String())
+	}
+}
+
+// floatBits returns the bits of f, treating negative zero as positive zero since they are equal.
+func floatBits(f float64) uint64 {
+	if f == 0 {
+		return 0
+	}
+	return math.Float64bits(f)
+}
+
+func hashBytes[T string | []byte](h uint64, b T) uint64 {
+	h = mixWord(h, uint64(len(b)))
+	for ; len(b) >= 8; b = b[8:] {
+		h = mixWord(h, uint64(b[0])|uint64(b[1])<<8|uint64(b[2])<<16|uint64(b[3])<<24|
+			uint64(b[4])<<32|uint64(b[5])<<40|uint64(b[6])<<48|uint64(b[7])<<56)
+	}
+	if len(b) > 0 {
+		var tail [8]byte
+		copy(tail[:], b)
+		h = mixWord(h, binary.LittleEndian.Uint64(tail[:]))
+	}
+	return h
+}
+
+func mixWord(h, w uint64) uint64 {
+	h = (h ^ w) * seedMultiplier
+	return h ^ (h >> 32)
+}
+
+// finalize spreads the entropy of h to all bits, as the trie consumes the low bits first.
+func finalize(h uint64) uint64 {
+	h ^= h >> 33
+	h *= seedPrime
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,100 @@
+package champ
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestHashSeeded(t *testing.T) {
+	// golden values guarantee hashes are stable across processes and releases
+	for _, tt := range []struct {
+		name     string
+		hash     uint64
+		expected uint64
+	}{
+		{
+			name:     "string",
+			hash:     hashSeeded(Seed(42), "key0"),
+			expected: 0xf3be4a1e9f4ad8e,
+		},
+		{
+			name:     "int",
+			hash:     hashSeeded(Seed(42), 12345),
+			expected: 0x71b81f8261ace38d,
+		},
+		{
+			name: "struct",
+			hash: hashSeeded(Seed(0), struct {
+				A int
+				B string
+			}{1, "x"}),
+			expected: 0x4fad36da947b99e,
+		},
+		{
+			name:     "seed from bytes",
+			hash:     uint64(SeedFromBytes([]byte("golden"))),
+			expected: 0x45e768ac22144af9,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.hash != tt.expected {
+				t.Errorf("hash = %#x, expected %#x", tt.hash, tt.expected)
+			}
+		})
+	}
+
+	t.Run("equal keys", func(t *testing.T) {
+		type key struct {
+			name string
+			f    float64
+			a    [2]int8
+			i    any
+		}
+		k1 := key{name: "a", f: 0, a: [2]int8{1, -1}, i: "x"}
+		k2 := key{name: "a", f: math.Copysign(0, -1), a: [2]int8{1, -1}, i: "x"}
+		if k1 != k2 {
+			t.Fatal("keys are expected to be equal")
+		}
+		if hashSeeded(Seed(1), k1) != hashSeeded(Seed(1), k2) {
+			t.Error("equal keys have different hashes")
+		}
+		if hashSeeded(Seed(1), k1) == hashSeeded(Seed(2), k1) {
+			t.Error("different seeds produce the same hash")
+		}
+	})
+}
+
+func TestNewWithSeed(t *testing.T) {
+	build := func(seed Seed) *Map[string, int] {
+		m := NewWithSeed[string, int](seed)
+		for i := range 10 {
+			m = m.Set(fmt.Sprintf("key%d", i), i)
+		}
+		return m
+	}
+
+	t.Run("deterministic iteration order", func(t *testing.T) {
+		expected := []string{"key1", "key7", "key2", "key9", "key4", "key6", "key3", "key8", "key0", "key5"}
+		if actual := slices.Collect(build(Seed(42)).Keys()); !slices.Equal(actual, expected) {
+			t.Errorf("Keys() = %v, expected %v", actual, expected)
+		}
+	})
+
+	t.Run("maps sharing a seed", func(t *testing.T) {
+		m1, m2 := build(Seed(42)), build(Seed(42))
+		if !sameHasher(m1, m2) {
+			t.Error("maps with equal seeds do not share the hash function")
+		}
+		if !Equal(m1, m2) {
+			t.Error("Equal() = false for maps with equal seeds")
+		}
+		if sameHasher(m1, build(Seed(43))) {
+			t.Error("maps with different seeds share the hash function")
+		}
+		if !Equal(m1, build(Seed(43))) {
+			t.Error("Equal() = false for maps with different seeds")
+		}
+	})
+}
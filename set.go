@@ -0,0 +1,480 @@
+package champ
+
+import (
+	"iter"
+	"slices"
+)
+
+// Set represents a persistent set backed by a CHAMP trie.
+//
+// The nodes of a set hold elements only, without the values array of the nodes of Map.
+// The zero value is an empty set ready to use.
+type Set[K comparable] struct {
+	root   setNode[K]
+	size   int
+	hasher *hasher[K] // nil for the default hash function
+}
+
+// NewSet creates a new set containing the given elements.
+func NewSet[K comparable](elems ...K) *Set[K] {
+	if len(elems) == 0 {
+		return &Set[K]{}
+	}
+
+	entries := make([]buildEntry[K, struct{}], len(elems))
+	for i, e := range elems {
+		entries[i].key = e
+	}
+	m := buildMap(nil, entries)
+	return &Set[K]{root: newSetNode(m.root), size: m.size}
+}
+
+// NewSetWithHasher creates a new empty set which hashes elements with the given function.
+//
+// The function must return equal hashes for equal elements.
+func NewSetWithHasher[K comparable](hash func(elem K) uint64) *Set[K] {
+	return &Set[K]{hasher: &hasher[K]{fn: hash}}
+}
+
+// Add returns a set that also contains elem.
+// If elem is already present, s itself is returned.
+func (s *Set[K]) Add(elem K) *Set[K] {
+	h := s.hasher.hash(elem)
+	if s.root == nil {
+		return &Set[K]{
+			root:   &bitmapIndexedSetNode[K]{datamap: uint32(1 << (h & bitMask)), keys: []K{elem}},
+			size:   1,
+			hasher: s.hasher,
+		}
+	}
+
+	root, added := s.root.add(elem, h, 0, s.hasher.hashFunc())
+	if !added {
+		return s
+	}
+	return &Set[K]{root: root, size: s.size + 1, hasher: s.hasher}
+}
+
+// Remove returns a set without elem.
+// If elem is not present, s itself is returned.
+func (s *Set[K]) Remove(elem K) *Set[K] {
+	if s.root == nil {
+		return s
+	}
+
+	root, removed := s.root.remove(elem, s.hasher.hash(elem), 0)
+	if !removed {
+		return s
+	}
+	return &Set[K]{root: root, size: s.size - 1, hasher: s.hasher}
+}
+
+// Contains reports whether elem is in the set.
+func (s *Set[K]) Contains(elem K) bool {
+	if s.root == nil {
+		return false
+	}
+	return s.root.contains(elem, s.hasher.hash(elem), 0)
+}
+
+// Len returns the number of elements
+func (s *Set[K]) Len() int {
+	return s.size
+}
+
+// All returns an iterator over the elements.
+func (s *Set[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		allSetNode(s.root, yield)
+	}
+}
+
+// Equal checks if two sets contain the same elements.
+//
+// Sets sharing a hash function are compared structurally.
+// Otherwise every element of s is looked up in other.
+func (s *Set[K]) Equal(other *Set[K]) bool {
+	if s.size != other.size {
+		return false
+	}
+	if !s.hasher.same(other.hasher) {
+		return s.IsSubset(other)
+	}
+	return equalSetNode(s.root, other.root)
+}
+
+// IsSubset reports whether every element of s is also in other.
+func (s *Set[K]) IsSubset(other *Set[K]) bool {
+	if s.size > other.size {
+		return false
+	}
+	if s.root == nil {
+		return true
+	}
+	if !s.hasher.same(other.hasher) {
+		for elem := range s.All() {
+			if !other.Contains(elem) {
+				return false
+			}
+		}
+		return true
+	}
+	return subsetSetNode(s.root, other.root, 0, s.hasher.hashFunc())
+}
+
+// IsDisjoint reports whether s and other have no elements in common.
+func (s *Set[K]) IsDisjoint(other *Set[K]) bool {
+	if s.root == nil || other.root == nil {
+		return true
+	}
+	if !s.hasher.same(other.hasher) {
+		for elem := range s.All() {
+			if other.Contains(elem) {
+				return false
+			}
+		}
+		return true
+	}
+	return disjointSetNode(s.root, other.root, 0, s.hasher.hashFunc())
+}
+
+// Union returns a set containing the elements of both s and other.
+func (s *Set[K]) Union(other *Set[K]) *Set[K] {
+	return s.combine(other, setUnion)
+}
+
+// Intersection returns a set containing the elements present in both s and other.
+func (s *Set[K]) Intersection(other *Set[K]) *Set[K] {
+	return s.combine(other, setIntersection)
+}
+
+// Difference returns a set containing the elements of s that are not in other.
+func (s *Set[K]) Difference(other *Set[K]) *Set[K] {
+	return s.combine(other, setDifference)
+}
+
+// SymmetricDifference returns a set containing the elements present in exactly one of s and other.
+func (s *Set[K]) SymmetricDifference(other *Set[K]) *Set[K] {
+	return s.combine(other, setSymmetricDifference)
+}
+
+// setOp is a set operation, which keeps an element depending on whether it is in the first and the second set.
+type setOp int
+
+const (
+	setUnion setOp = iota
+	setIntersection
+	setDifference
+	setSymmetricDifference
+)
+
+// keep reports whether op keeps an element present in the first set if in1, and in the second if in2.
+func (op setOp) keep(in1, in2 bool) bool {
+	switch op {
+	case setUnion:
+		return in1 || in2
+	case setIntersection:
+		return in1 && in2
+	case setDifference:
+		return in1 && !in2
+	default:
+		return in1 != in2
+	}
+}
+
+// combine returns the result of op on s and other, which uses the hash function of s.
+//
+// Sets sharing a hash function are walked together, reusing or dropping subtrees present in only one of them.
+// Otherwise every element of both sets is looked up in the other.
+func (s *Set[K]) combine(other *Set[K], op setOp) *Set[K] {
+	if !s.hasher.same(other.hasher) {
+		result := &Set[K]{hasher: s.hasher}
+		for elem := range s.All() {
+			if op.keep(true, other.Contains(elem)) {
+				result = result.Add(elem)
+			}
+		}
+		if op.keep(false, true) {
+			for elem := range other.All() {
+				if !s.Contains(elem) {
+					result = result.Add(elem)
+				}
+			}
+		}
+		return result
+	}
+
+	var root setNode[K]
+	var common int
+	switch {
+	case s.root == nil && other.root == nil:
+	case s.root == nil:
+		if op.keep(false, true) {
+			root = other.root
+		}
+	case other.root == nil:
+		if op.keep(true, false) {
+			root = s.root
+		}
+	default:
+		root, common = combineSetNode(s.root, other.root, 0, s.hasher.hashFunc(), op)
+	}
+
+	size := 0
+	if op.keep(true, false) {
+		size += s.size - common
+	}
+	if op.keep(false, true) {
+		size += other.size - common
+	}
+	if op.keep(true, true) {
+		size += common
+	}
+
+	switch root {
+	case nil:
+		return &Set[K]{hasher: s.hasher}
+	case s.root:
+		return s
+	}
+	return &Set[K]{root: root, size: size, hasher: s.hasher}
+}
+
+// combineSetNode returns the subtree holding the keys of n1 and n2 kept by op,
+// and the number of keys present in both.
+func combineSetNode[K comparable](n1, n2 setNode[K], shift uint, hashFunc func(key K) uint64, op setOp) (setNode[K], int) {
+	// short-circuit for identical pointers
+	if n1 == n2 {
+		if op.keep(true, true) {
+			return n1, countSetNode(n1)
+		}
+		return nil, countSetNode(n1)
+	}
+
+	if n1, ok := n1.(*bitmapIndexedSetNode[K]); ok {
+		return combineBitmapIndexedSetNodes(n1, n2.(*bitmapIndexedSetNode[K]), shift, hashFunc, op)
+	}
+
+	// Nodes of different kinds never meet, so both are collision nodes at the maximum depth.
+	c1, c2 := n1.(*collisionSetNode[K]), n2.(*collisionSetNode[K])
+	keys := make([]K, 0, len(c1.keys)+len(c2.keys))
+	common := 0
+	for _, k1 := range c1.keys {
+		in2 := slices.Contains(c2.keys, k1)
+		if in2 {
+			common++
+		}
+		if op.keep(true, in2) {
+			keys = append(keys, k1)
+		}
+	}
+	if op.keep(false, true) {
+		for _, k2 := range c2.keys {
+			if !slices.Contains(c1.keys, k2) {
+				keys = append(keys, k2)
+			}
+		}
+	}
+	return newCollisionSetNode(keys), common
+}
+
+func combineBitmapIndexedSetNodes[K comparable](
+	n1, n2 *bitmapIndexedSetNode[K],
+	shift uint,
+	hashFunc func(key K) uint64,
+	op setOp,
+) (setNode[K], int) {
+	result := &bitmapIndexedSetNode[K]{}
+	common := 0
+
+	for bits := n1.datamap | n1.nodemap | n2.datamap | n2.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+		in1 := (n1.datamap|n1.nodemap)&bit != 0
+		in2 := (n2.datamap|n2.nodemap)&bit != 0
+
+		switch {
+		case !in2:
+			if op.keep(true, false) {
+				appendSetPosition(result, n1, bit)
+			}
+		case !in1:
+			if op.keep(false, true) {
+				appendSetPosition(result, n2, bit)
+			}
+		case n1.datamap&bit != 0 && n2.datamap&bit != 0 && n1.keys[popcount(n1.datamap&(bit-1))] == n2.keys[popcount(n2.datamap&(bit-1))]:
+			common++
+			if op.keep(true, true) {
+				result.appendKey(bit, n1.keys[popcount(n1.datamap&(bit-1))])
+			}
+		default:
+			// A key meeting a sub-node or another key is combined as a single key sub-node
+			child1 := subSetNode(n1, bit, shift+bitsPerLevel, hashFunc)
+			child2 := subSetNode(n2, bit, shift+bitsPerLevel, hashFunc)
+			child, c := combineSetNode(child1, child2, shift+bitsPerLevel, hashFunc, op)
+			result.appendChild(bit, child)
+			common += c
+		}
+	}
+
+	return result.orNil(), common
+}
+
+// appendSetPosition appends the key or the sub-node of n at bit to result.
+func appendSetPosition[K comparable](result, n *bitmapIndexedSetNode[K], bit uint32) {
+	if n.datamap&bit != 0 {
+		result.appendKey(bit, n.keys[popcount(n.datamap&(bit-1))])
+		return
+	}
+	result.appendNode(bit, n.nodes[popcount(n.nodemap&(bit-1))])
+}
+
+// subSetNode returns the sub-node of n at bit, or a sub-node at shift holding the key of n at bit.
+func subSetNode[K comparable](n *bitmapIndexedSetNode[K], bit uint32, shift uint, hashFunc func(key K) uint64) setNode[K] {
+	if n.nodemap&bit != 0 {
+		return n.nodes[popcount(n.nodemap&(bit-1))]
+	}
+
+	key := n.keys[popcount(n.datamap&(bit-1))]
+	if shift >= maxDepth*bitsPerLevel {
+		return &collisionSetNode[K]{keys: []K{key}}
+	}
+	return &bitmapIndexedSetNode[K]{
+		datamap: uint32(1 << ((hashFunc(key) >> shift) & bitMask)),
+		keys:    []K{key},
+	}
+}
+
+func equalSetNode[K comparable](n1, n2 setNode[K]) bool {
+	// short-circuit for identical pointers
+	if n1 == n2 {
+		return true
+	}
+
+	switch n1 := n1.(type) {
+	case *bitmapIndexedSetNode[K]:
+		n2, ok := n2.(*bitmapIndexedSetNode[K])
+		if !ok || n1.datamap != n2.datamap || n1.nodemap != n2.nodemap || !slices.Equal(n1.keys, n2.keys) {
+			return false
+		}
+		for i := range n1.nodes {
+			if !equalSetNode(n1.nodes[i], n2.nodes[i]) {
+				return false
+			}
+		}
+		return true
+	case *collisionSetNode[K]:
+		n2, ok := n2.(*collisionSetNode[K])
+		if !ok || len(n1.keys) != len(n2.keys) {
+			return false
+		}
+		// the keys of collision nodes are compared regardless of their order
+		for _, k := range n1.keys {
+			if !slices.Contains(n2.keys, k) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// subsetSetNode reports whether every key of n1 is also in n2.
+func subsetSetNode[K comparable](n1, n2 setNode[K], shift uint, hashFunc func(key K) uint64) bool {
+	// short-circuit for identical pointers
+	if n1 == n2 {
+		return true
+	}
+
+	b1, ok := n1.(*bitmapIndexedSetNode[K])
+	if !ok {
+		// both are collision nodes at the maximum depth
+		for _, k := range n1.(*collisionSetNode[K]).keys {
+			if !n2.contains(k, 0, shift) {
+				return false
+			}
+		}
+		return true
+	}
+
+	b2 := n2.(*bitmapIndexedSetNode[K])
+	for bits := b1.datamap | b1.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		if b1.datamap&bit != 0 {
+			k1 := b1.keys[popcount(b1.datamap&(bit-1))]
+			switch {
+			case b2.datamap&bit != 0:
+				if b2.keys[popcount(b2.datamap&(bit-1))] != k1 {
+					return false
+				}
+			case b2.nodemap&bit != 0:
+				if !b2.nodes[popcount(b2.nodemap&(bit-1))].contains(k1, hashFunc(k1), shift+bitsPerLevel) {
+					return false
+				}
+			default:
+				return false
+			}
+			continue
+		}
+
+		// a sub-node holds at least two keys, which cannot all be in a single key of n2
+		if b2.nodemap&bit == 0 {
+			return false
+		}
+		child1 := b1.nodes[popcount(b1.nodemap&(bit-1))]
+		child2 := b2.nodes[popcount(b2.nodemap&(bit-1))]
+		if !subsetSetNode(child1, child2, shift+bitsPerLevel, hashFunc) {
+			return false
+		}
+	}
+	return true
+}
+
+// disjointSetNode reports whether n1 and n2 have no keys in common.
+func disjointSetNode[K comparable](n1, n2 setNode[K], shift uint, hashFunc func(key K) uint64) bool {
+	// identical nodes hold at least one key in common
+	if n1 == n2 {
+		return false
+	}
+
+	b1, ok := n1.(*bitmapIndexedSetNode[K])
+	if !ok {
+		// both are collision nodes at the maximum depth
+		for _, k := range n1.(*collisionSetNode[K]).keys {
+			if n2.contains(k, 0, shift) {
+				return false
+			}
+		}
+		return true
+	}
+
+	b2 := n2.(*bitmapIndexedSetNode[K])
+	for bits := (b1.datamap | b1.nodemap) & (b2.datamap | b2.nodemap); bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		switch {
+		case b1.datamap&bit != 0:
+			k1 := b1.keys[popcount(b1.datamap&(bit-1))]
+			if b2.datamap&bit != 0 {
+				if b2.keys[popcount(b2.datamap&(bit-1))] == k1 {
+					return false
+				}
+			} else if b2.nodes[popcount(b2.nodemap&(bit-1))].contains(k1, hashFunc(k1), shift+bitsPerLevel) {
+				return false
+			}
+		case b2.datamap&bit != 0:
+			k2 := b2.keys[popcount(b2.datamap&(bit-1))]
+			if b1.nodes[popcount(b1.nodemap&(bit-1))].contains(k2, hashFunc(k2), shift+bitsPerLevel) {
+				return false
+			}
+		default:
+			child1 := b1.nodes[popcount(b1.nodemap&(bit-1))]
+			child2 := b2.nodes[popcount(b2.nodemap&(bit-1))]
+			if !disjointSetNode(child1, child2, shift+bitsPerLevel, hashFunc) {
+				return false
+			}
+		}
+	}
+	return true
+}
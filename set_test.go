@@ -0,0 +1,225 @@
+package champ
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	t.Run("basic operations", func(t *testing.T) {
+		var s Set[string]
+
+		s1 := s.Add("a").Add("b").Add("c")
+		if s1.Len() != 3 {
+			t.Fatalf("Len() expected %d, actual %d", 3, s1.Len())
+		}
+		if s2 := s1.Add("a"); s2 != s1 {
+			t.Error("Add() of an existing element returned a new set")
+		}
+
+		s3 := s1.Remove("b")
+		if s3.Len() != 2 {
+			t.Fatalf("Len() expected %d, actual %d", 2, s3.Len())
+		}
+		if s3.Contains("b") {
+			t.Errorf("Contains(%q) expected false after Remove", "b")
+		}
+		if !s1.Contains("b") {
+			t.Errorf("Contains(%q) expected true on original set", "b")
+		}
+		if s4 := s3.Remove("b"); s4 != s3 {
+			t.Error("Remove() of a missing element returned a new set")
+		}
+	})
+
+	t.Run("large set", func(t *testing.T) {
+		const n = 2048
+
+		s := NewSet[int]()
+		for i := range n {
+			s = s.Add(i)
+		}
+		for i := 0; i < n; i += 2 {
+			s = s.Remove(i)
+		}
+
+		if s.Len() != n/2 {
+			t.Fatalf("Len() expected %d, actual %d", n/2, s.Len())
+		}
+		for i := range n {
+			if s.Contains(i) != (i%2 == 1) {
+				t.Errorf("Contains(%d) expected %v", i, i%2 == 1)
+			}
+		}
+	})
+}
+
+func TestSetAll(t *testing.T) {
+	elems := make([]string, 1024)
+	for i := range elems {
+		elems[i] = fmt.Sprintf("elem%d", i)
+	}
+
+	actual := slices.Sorted(NewSet(elems...).All())
+	expected := slices.Sorted(slices.Values(elems))
+	if !slices.Equal(actual, expected) {
+		t.Errorf("All() = %v, expected %v", actual, expected)
+	}
+}
+
+func TestSetAlgebra(t *testing.T) {
+	rangeSet := func(start, end int) *Set[int] {
+		s := NewSet[int]()
+		for i := start; i < end; i++ {
+			s = s.Add(i)
+		}
+		return s
+	}
+
+	for _, tt := range []struct {
+		name                            string
+		s1, s2                          *Set[int]
+		union, intersection             *Set[int]
+		difference, symmetricDifference *Set[int]
+	}{
+		{
+			name:                "empty",
+			s1:                  NewSet[int](),
+			s2:                  NewSet[int](),
+			union:               NewSet[int](),
+			intersection:        NewSet[int](),
+			difference:          NewSet[int](),
+			symmetricDifference: NewSet[int](),
+		},
+		{
+			name:                "one empty",
+			s1:                  NewSet(1, 2, 3),
+			s2:                  NewSet[int](),
+			union:               NewSet(1, 2, 3),
+			intersection:        NewSet[int](),
+			difference:          NewSet(1, 2, 3),
+			symmetricDifference: NewSet(1, 2, 3),
+		},
+		{
+			name:                "small sets",
+			s1:                  NewSet(1, 2, 3),
+			s2:                  NewSet(2, 3, 4),
+			union:               NewSet(1, 2, 3, 4),
+			intersection:        NewSet(2, 3),
+			difference:          NewSet(1),
+			symmetricDifference: NewSet(1, 4),
+		},
+		{
+			name:                "large overlapping sets",
+			s1:                  rangeSet(0, 2000),
+			s2:                  rangeSet(1000, 3000),
+			union:               rangeSet(0, 3000),
+			intersection:        rangeSet(1000, 2000),
+			difference:          rangeSet(0, 1000),
+			symmetricDifference: rangeSet(0, 1000).Union(rangeSet(2000, 3000)),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.s1.Union(tt.s2); !actual.Equal(tt.union) {
+				t.Errorf("Union() = %v, expected %v", slices.Sorted(actual.All()), slices.Sorted(tt.union.All()))
+			}
+			if actual := tt.s1.Intersection(tt.s2); !actual.Equal(tt.intersection) {
+				t.Errorf("Intersection() = %v, expected %v", slices.Sorted(actual.All()), slices.Sorted(tt.intersection.All()))
+			}
+			if actual := tt.s1.Difference(tt.s2); !actual.Equal(tt.difference) {
+				t.Errorf("Difference() = %v, expected %v", slices.Sorted(actual.All()), slices.Sorted(tt.difference.All()))
+			}
+			if actual := tt.s1.SymmetricDifference(tt.s2); !actual.Equal(tt.symmetricDifference) {
+				t.Errorf("SymmetricDifference() = %v, expected %v", slices.Sorted(actual.All()), slices.Sorted(tt.symmetricDifference.All()))
+			}
+		})
+	}
+}
+
+func TestSetHashers(t *testing.T) {
+	// low entropy hash function producing deep tries and collision nodes
+	lowEntropy := NewSetWithHasher(func(elem int) uint64 { return uint64(elem%7) << 58 })
+
+	for _, tt := range []struct {
+		name   string
+		s1, s2 *Set[int] // empty sets to start from
+	}{
+		{
+			name: "default hash function",
+			s1:   &Set[int]{},
+			s2:   &Set[int]{},
+		},
+		{
+			name: "shared low entropy hash function",
+			s1:   lowEntropy,
+			s2:   lowEntropy,
+		},
+		{
+			name: "different hash functions",
+			s1:   &Set[int]{},
+			s2:   lowEntropy,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s1, s2 := tt.s1, tt.s2
+			in1, in2 := map[int]bool{}, map[int]bool{}
+			for i := range 300 {
+				s1, in1[i] = s1.Add(i), true
+			}
+			for i := 200; i < 400; i++ {
+				s2, in2[i] = s2.Add(i), true
+			}
+			// share subtrees between the sets
+			s3 := s1.Remove(0).Add(1000)
+			in3 := map[int]bool{1000: true}
+			for i := 1; i < 300; i++ {
+				in3[i] = true
+			}
+
+			for _, pair := range []struct {
+				name     string
+				a, b     *Set[int]
+				inA, inB map[int]bool
+			}{
+				{name: "overlapping", a: s1, b: s2, inA: in1, inB: in2},
+				{name: "sharing subtrees", a: s1, b: s3, inA: in1, inB: in3},
+			} {
+				for _, op := range []struct {
+					name   string
+					actual *Set[int]
+					keep   func(inA, inB bool) bool
+				}{
+					{name: "Union", actual: pair.a.Union(pair.b), keep: func(inA, inB bool) bool { return inA || inB }},
+					{name: "Intersection", actual: pair.a.Intersection(pair.b), keep: func(inA, inB bool) bool { return inA && inB }},
+					{name: "Difference", actual: pair.a.Difference(pair.b), keep: func(inA, inB bool) bool { return inA && !inB }},
+					{name: "SymmetricDifference", actual: pair.a.SymmetricDifference(pair.b), keep: func(inA, inB bool) bool { return inA != inB }},
+				} {
+					// the result uses the hash function of the first set, so it is compared structurally
+					expected := tt.s1
+					for i := range 1001 {
+						if op.keep(pair.inA[i], pair.inB[i]) {
+							expected = expected.Add(i)
+						}
+					}
+					if op.actual.Len() != expected.Len() {
+						t.Errorf("%s %s() Len() expected %d, actual %d", pair.name, op.name, expected.Len(), op.actual.Len())
+					}
+					if !op.actual.Equal(expected) {
+						t.Errorf("%s %s() expected %v, actual %v", pair.name, op.name, slices.Sorted(expected.All()), slices.Sorted(op.actual.All()))
+					}
+				}
+			}
+
+			if !s1.Intersection(s2).IsSubset(s2) || s1.IsSubset(s2) {
+				t.Error("IsSubset() expected true for the intersection only")
+			}
+			if !s1.Difference(s2).IsDisjoint(s2) || s1.IsDisjoint(s2) {
+				t.Error("IsDisjoint() expected true for the difference only")
+			}
+			if !s1.Equal(s1.Remove(0).Add(0)) {
+				t.Error("Equal() = false for a set with an element removed and added back")
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package champ
+
+import "unsafe"
+
+// Stats describes the shape of the trie of a map.
+type Stats struct {
+	Entries        int // number of entries
+	BitmapNodes    int // number of bitmap indexed nodes
+	CollisionNodes int // number of collision nodes holding keys with identical hashes
+
+	// MaxDepth and AverageDepth are the maximum and average depths of the entries,
+	// where the entries of the root are at depth 1.
+	MaxDepth     int
+	AverageDepth float64
+
+	// Fanout counts the bitmap indexed nodes by their number of entries and children.
+	Fanout [branchFactor + 1]int
+
+	// MaxCollision is the number of entries of the largest collision node.
+	MaxCollision int
+
+	// Bytes estimates the memory held by the nodes, including the backing arrays of their slices.
+	// Memory referenced by keys and values themselves, such as the bytes of strings, is not included.
+	Bytes int
+}
+
+// Stats returns statistics about the trie of the map, walking every node.
+//
+// It helps to diagnose pathological key distributions, which show up as deep tries and large collision nodes.
+func (m *Map[K, V]) Stats() Stats {
+	var s Stats
+	depths := 0
+	if m.root != nil {
+		statsNode(&s, &depths, m.root, 1)
+	}
+
+	if s.Entries > 0 {
+		s.AverageDepth = float64(depths) / float64(s.Entries)
+	}
+	return s
+}
+
+// statsNode adds the nodes and entries of the subtree n at depth to s,
+// and the sum of the depths of its entries to depths.
+func statsNode[K comparable, V any](s *Stats, depths *int, n node[K, V], depth int) {
+	s.Bytes += nodeBytes(n)
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		s.BitmapNodes++
+		s.Fanout[popcount(n.datamap|n.nodemap)]++
+		if len(n.keys) > 0 {
+			s.Entries += len(n.keys)
+			*depths += len(n.keys) * depth
+			s.MaxDepth = max(s.MaxDepth, depth)
+		}
+		for _, child := range n.nodes {
+			statsNode(s, depths, child, depth+1)
+		}
+	case *collisionNode[K, V]:
+		s.CollisionNodes++
+		s.Entries += len(n.keys)
+		*depths += len(n.keys) * depth
+		s.MaxDepth = max(s.MaxDepth, depth)
+		s.MaxCollision = max(s.MaxCollision, len(n.keys))
+	}
+}
+
+// nodeBytes estimates the memory held by n itself, excluding its children.
+func nodeBytes[K comparable, V any](n node[K, V]) int {
+	var (
+		k K
+		v V
+		c node[K, V]
+	)
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		return int(unsafe.Sizeof(*n)) +
+			cap(n.keys)*int(unsafe.Sizeof(k)) +
+			cap(n.values)*int(unsafe.Sizeof(v)) +
+			cap(n.nodes)*int(unsafe.Sizeof(c))
+	case *collisionNode[K, V]:
+		return int(unsafe.Sizeof(*n)) +
+			cap(n.keys)*int(unsafe.Sizeof(k)) +
+			cap(n.values)*int(unsafe.Sizeof(v))
+	}
+	return 0
+}
+
+// SharingStats describes the structural sharing between maps.
+type SharingStats struct {
+	Versions []VersionStats // statistics of each map in the order given to SharedStats
+
+	Nodes int // number of distinct nodes of all maps
+	Bytes int // estimated memory held by the distinct nodes of all maps
+
+	SharedNodes int // number of nodes reachable from more than one map
+	SharedBytes int // estimated memory held by the shared nodes
+}
+
+// VersionStats describes the nodes of a map in SharingStats.
+type VersionStats struct {
+	Nodes int // number of nodes reachable from the map
+	Bytes int // estimated memory held by the nodes reachable from the map
+
+	// UniqueNodes and UniqueBytes count the nodes reachable from no other map,
+	// which is what retaining the map costs on top of the others.
+	UniqueNodes int
+	UniqueBytes int
+}
+
+// SharedStats reports how many nodes and bytes are unique to each of the maps versus shared between them,
+// by comparing node pointers. Bytes are estimated as in Stats.
+//
+// Every node is walked once per map reaching it through an unshared parent,
+// so the cost is proportional to the number of distinct nodes rather than the sum of the map sizes.
+func SharedStats[K comparable, V any](maps ...*Map[K, V]) SharingStats {
+	s := SharingStats{Versions: make([]VersionStats, len(maps))}
+	seen := make(map[node[K, V]]*sharedNode)
+	for i, m := range maps {
+		if m.root != nil {
+			s.Versions[i].Nodes, s.Versions[i].Bytes = shareNode(seen, m.root, i)
+		}
+	}
+
+	for _, e := range seen {
+		s.Nodes++
+		s.Bytes += e.bytes
+		if e.owner == sharedOwner {
+			s.SharedNodes++
+			s.SharedBytes += e.bytes
+		} else {
+			s.Versions[e.owner].UniqueNodes++
+			s.Versions[e.owner].UniqueBytes += e.bytes
+		}
+	}
+	return s
+}
+
+// sharedOwner is the owner of a node reachable from more than one map.
+const sharedOwner = -1
+
+// sharedNode records the owner of a node and the size of its subtree for SharedStats.
+type sharedNode struct {
+	owner        int // index of the only map reaching the node, or sharedOwner
+	bytes        int // estimated memory held by the node itself
+	subtreeNodes int
+	subtreeBytes int
+}
+
+// shareNode records that the subtree n is reachable from the map at index version,
+// and returns the number of nodes and estimated bytes of the subtree.
+func shareNode[K comparable, V any](seen map[node[K, V]]*sharedNode, n node[K, V], version int) (int, int) {
+	e, ok := seen[n]
+	if ok {
+		if e.owner != version && e.owner != sharedOwner {
+			// The subtree is reached from a second map, so every node in it becomes shared.
+			// Subtrees that are already shared stop the descent.
+			e.owner = sharedOwner
+			if n, ok := n.(*bitmapIndexedNode[K, V]); ok {
+				for _, child := range n.nodes {
+					shareNode(seen, child, version)
+				}
+			}
+		}
+		return e.subtreeNodes, e.subtreeBytes
+	}
+
+	e = &sharedNode{owner: version, bytes: nodeBytes(n)}
+	seen[n] = e
+	e.subtreeNodes, e.subtreeBytes = 1, e.bytes
+	if n, ok := n.(*bitmapIndexedNode[K, V]); ok {
+		for _, child := range n.nodes {
+			nodes, bytes := shareNode(seen, child, version)
+			e.subtreeNodes += nodes
+			e.subtreeBytes += bytes
+		}
+	}
+	return e.subtreeNodes, e.subtreeBytes
+}
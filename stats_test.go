@@ -0,0 +1,180 @@
+package champ
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// collectNodes adds the nodes of the subtree n to nodes.
+func collectNodes[K comparable, V any](nodes map[node[K, V]]bool, n node[K, V]) {
+	if n == nil {
+		return
+	}
+	nodes[n] = true
+	if n, ok := n.(*bitmapIndexedNode[K, V]); ok {
+		for _, child := range n.nodes {
+			collectNodes(nodes, child)
+		}
+	}
+}
+
+func TestStats(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		keys     []string
+		expected Stats
+	}{
+		{
+			name:     "empty",
+			keys:     nil,
+			expected: Stats{},
+		},
+		{
+			name: "root entries",
+			keys: []string{"0", "1"},
+			expected: Stats{
+				Entries:      2,
+				BitmapNodes:  1,
+				MaxDepth:     1,
+				AverageDepth: 1,
+				Fanout:       [branchFactor + 1]int{2: 1},
+			},
+		},
+		{
+			name: "sub-node",
+			keys: []string{"0", "1", "100000"},
+			expected: Stats{
+				Entries:      3,
+				BitmapNodes:  2,
+				MaxDepth:     2,
+				AverageDepth: 5.0 / 3,
+				Fanout:       [branchFactor + 1]int{2: 2},
+			},
+		},
+		{
+			name: "collision",
+			keys: []string{"1", "01", "001"},
+			expected: Stats{
+				Entries:        3,
+				BitmapNodes:    maxDepth,
+				CollisionNodes: 1,
+				MaxDepth:       maxDepth + 1,
+				AverageDepth:   maxDepth + 1,
+				Fanout:         [branchFactor + 1]int{1: maxDepth},
+				MaxCollision:   3,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewWithHasher[string, int](testHashFunc)
+			for i, key := range tt.keys {
+				m = m.Set(key, i)
+			}
+
+			actual := m.Stats()
+			if (actual.Bytes > 0) != (len(tt.keys) > 0) {
+				t.Errorf("Stats().Bytes expected to be positive for a non-empty map, actual %d", actual.Bytes)
+			}
+			actual.Bytes = 0
+			if actual != tt.expected {
+				t.Errorf("Stats() expected %+v, actual %+v", tt.expected, actual)
+			}
+		})
+	}
+
+	t.Run("large map", func(t *testing.T) {
+		m := New[string, int]()
+		for i := range 10000 {
+			m = m.Set(fmt.Sprintf("k%d", i), i)
+		}
+
+		s := m.Stats()
+		if s.Entries != m.Len() {
+			t.Errorf("Stats().Entries expected %d, actual %d", m.Len(), s.Entries)
+		}
+		nodes := 0
+		for _, n := range s.Fanout {
+			nodes += n
+		}
+		if nodes != s.BitmapNodes {
+			t.Errorf("Stats().Fanout counts %d nodes, expected %d", nodes, s.BitmapNodes)
+		}
+		if s.AverageDepth < 1 || s.AverageDepth > float64(s.MaxDepth) {
+			t.Errorf("Stats().AverageDepth %v out of range [1, %d]", s.AverageDepth, s.MaxDepth)
+		}
+		if smaller := m.Delete("k0").Stats(); smaller.Bytes > s.Bytes {
+			t.Errorf("Stats().Bytes expected to shrink on delete, %d > %d", smaller.Bytes, s.Bytes)
+		}
+	})
+}
+
+func TestSharedStats(t *testing.T) {
+	base := New[string, int]()
+	for i := range 5000 {
+		base = base.Set(fmt.Sprintf("k%d", i), i)
+	}
+	b := base.Transient()
+	for i := range 100 {
+		b.Delete(fmt.Sprintf("k%d", i))
+	}
+	built := b.Persistent()
+
+	for _, tt := range []struct {
+		name string
+		maps []*Map[string, int]
+	}{
+		{name: "no maps", maps: nil},
+		{name: "single map", maps: []*Map[string, int]{base}},
+		{name: "empty map", maps: []*Map[string, int]{New[string, int](), base}},
+		{name: "same map twice", maps: []*Map[string, int]{base, base}},
+		{name: "versions", maps: []*Map[string, int]{base, base.Set("k0", -1), base.Delete("k1").Set("new", 0), built}},
+		{name: "reversed versions", maps: []*Map[string, int]{built, base.Set("k0", -1), base}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// count the maps reaching every node
+			versions := make([]map[node[string, int]]bool, len(tt.maps))
+			reached := map[node[string, int]]int{}
+			for i, m := range tt.maps {
+				versions[i] = map[node[string, int]]bool{}
+				collectNodes(versions[i], m.root)
+				for n := range versions[i] {
+					reached[n]++
+				}
+			}
+
+			expected := SharingStats{Versions: make([]VersionStats, len(tt.maps))}
+			for n, count := range reached {
+				expected.Nodes++
+				expected.Bytes += nodeBytes(n)
+				if count > 1 {
+					expected.SharedNodes++
+					expected.SharedBytes += nodeBytes(n)
+				}
+			}
+			for i := range tt.maps {
+				for n := range versions[i] {
+					v := &expected.Versions[i]
+					v.Nodes++
+					v.Bytes += nodeBytes(n)
+					if reached[n] == 1 {
+						v.UniqueNodes++
+						v.UniqueBytes += nodeBytes(n)
+					}
+				}
+			}
+
+			actual := SharedStats(tt.maps...)
+			if !reflect.DeepEqual(actual, expected) {
+				t.Errorf("SharedStats() expected %+v, actual %+v", expected, actual)
+			}
+			for i, m := range tt.maps {
+				s := m.Stats()
+				if nodes := s.BitmapNodes + s.CollisionNodes; actual.Versions[i].Nodes != nodes || actual.Versions[i].Bytes != s.Bytes {
+					t.Errorf("SharedStats() version %d expected %d nodes and %d bytes as Stats(), actual %d and %d",
+						i, nodes, s.Bytes, actual.Versions[i].Nodes, actual.Versions[i].Bytes)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package champ
+
+// IsSubmap reports whether every entry of m1 is also present in m2 with an equal value.
+//
+// IsSubmap walks both tries together, returning false as soon as a position of m1 is empty in m2,
+// and skips subtrees shared by m1 and m2.
+// If the maps use different hash functions, every entry of m1 is looked up in m2 instead.
+func IsSubmap[K, V comparable](m1, m2 *Map[K, V]) bool {
+	return submapMap(m1, m2, equalValues[V])
+}
+
+// IsSubmapFunc is like IsSubmap but uses eq to compare values.
+// Subtrees shared by both maps are skipped without calling eq.
+func IsSubmapFunc[K comparable, V any](m1, m2 *Map[K, V], eq func(v1, v2 V) bool) bool {
+	return submapMap(m1, m2, eq)
+}
+
+// KeysSubset reports whether every key of m1 is also present in m2, regardless of values.
+func KeysSubset[K comparable, V, W any](m1 *Map[K, V], m2 *Map[K, W]) bool {
+	return submapMap(m1, m2, func(V, W) bool { return true })
+}
+
+// Disjoint reports whether m1 and m2 have no keys in common.
+//
+// Disjoint walks both tries together, only descending into positions occupied in both.
+// If the maps use different hash functions, every key of the smaller map is looked up in the other instead.
+func Disjoint[K comparable, V, W any](m1 *Map[K, V], m2 *Map[K, W]) bool {
+	if m1.root == nil || m2.root == nil {
+		return true
+	}
+	if !sameHasher(m1, m2) {
+		if m1.size <= m2.size {
+			for k := range m1.Keys() {
+				if _, ok := m2.Get(k); ok {
+					return false
+				}
+			}
+			return true
+		}
+		for k := range m2.Keys() {
+			if _, ok := m1.Get(k); ok {
+				return false
+			}
+		}
+		return true
+	}
+	return disjointNode(m1.root, m2.root, 0, m1.hasher.hashFunc())
+}
+
+// SubsetKeys reports whether every key of m is present in keys.
+//
+// SubsetKeys walks the tries of m and keys together like KeysSubset.
+// If they use different hash functions, every key of m is looked up in keys instead.
+func SubsetKeys[K comparable, V any](m *Map[K, V], keys *Set[K]) bool {
+	if m.size > keys.size {
+		return false
+	}
+	if m.root == nil {
+		return true
+	}
+	if !m.hasher.same(keys.hasher) {
+		for k := range m.Keys() {
+			if !keys.Contains(k) {
+				return false
+			}
+		}
+		return true
+	}
+	return subsetKeysNode(m.root, keys.root, 0, m.hasher.hashFunc())
+}
+
+// DisjointKeys reports whether m has no keys in keys.
+//
+// DisjointKeys walks the tries of m and keys together like Disjoint.
+// If they use different hash functions, every key of the smaller one is looked up in the other instead.
+func DisjointKeys[K comparable, V any](m *Map[K, V], keys *Set[K]) bool {
+	if m.root == nil || keys.root == nil {
+		return true
+	}
+	if !m.hasher.same(keys.hasher) {
+		if m.size <= keys.size {
+			for k := range m.Keys() {
+				if keys.Contains(k) {
+					return false
+				}
+			}
+			return true
+		}
+		for k := range keys.All() {
+			if _, ok := m.Get(k); ok {
+				return false
+			}
+		}
+		return true
+	}
+	return disjointKeysNode(m.root, keys.root, 0, m.hasher.hashFunc())
+}
+
+func submapMap[K comparable, V, W any](m1 *Map[K, V], m2 *Map[K, W], match func(v1 V, v2 W) bool) bool {
+	if m1.size > m2.size {
+		return false
+	}
+	if m1.root == nil {
+		return true
+	}
+	if !sameHasher(m1, m2) {
+		for k, v1 := range m1.All() {
+			if v2, ok := m2.Get(k); !ok || !match(v1, v2) {
+				return false
+			}
+		}
+		return true
+	}
+	return submapNode(m1.root, m2.root, 0, m1.hasher.hashFunc(), match)
+}
+
+// submapNode reports whether every entry of n1 is present in n2 with a matching value.
+func submapNode[K comparable, V, W any](
+	n1 node[K, V],
+	n2 node[K, W],
+	shift uint,
+	hashFunc func(key K) uint64,
+	match func(v1 V, v2 W) bool,
+) bool {
+	// short-circuit for identical pointers
+	if any(n1) == any(n2) {
+		return true
+	}
+
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedNode[K, W]); ok {
+			return submapBitmapIndexedNodes(n1, n2, shift, hashFunc, match)
+		}
+	}
+
+	// Collision nodes, and nodes of different kinds which never meet in a canonical trie.
+	for k, v1 := range allNode(n1) {
+		if v2, ok := nodeGet(n2, k, hashFunc(k), shift); !ok || !match(v1, v2) {
+			return false
+		}
+	}
+	return true
+}
+
+func submapBitmapIndexedNodes[K comparable, V, W any](
+	n1 *bitmapIndexedNode[K, V],
+	n2 *bitmapIndexedNode[K, W],
+	shift uint,
+	hashFunc func(key K) uint64,
+	match func(v1 V, v2 W) bool,
+) bool {
+	// A sub-node holds at least two entries, so it cannot be contained in a single entry.
+	if (n1.datamap|n1.nodemap)&^(n2.datamap|n2.nodemap) != 0 || n1.nodemap&n2.datamap != 0 {
+		return false
+	}
+
+	for bits := n1.datamap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+		idx1 := popcount(n1.datamap & (bit - 1))
+		k1, v1 := n1.keys[idx1], n1.values[idx1]
+
+		if n2.datamap&bit != 0 {
+			idx2 := popcount(n2.datamap & (bit - 1))
+			if n2.keys[idx2] != k1 || !match(v1, n2.values[idx2]) {
+				return false
+			}
+			continue
+		}
+
+		child := n2.nodes[popcount(n2.nodemap&(bit-1))]
+		if v2, ok := nodeGet(child, k1, hashFunc(k1), shift+bitsPerLevel); !ok || !match(v1, v2) {
+			return false
+		}
+	}
+
+	for bits := n1.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+		child1 := n1.nodes[popcount(n1.nodemap&(bit-1))]
+		child2 := n2.nodes[popcount(n2.nodemap&(bit-1))]
+		if !submapNode(child1, child2, shift+bitsPerLevel, hashFunc, match) {
+			return false
+		}
+	}
+	return true
+}
+
+// disjointNode reports whether n1 and n2 have no keys in common.
+func disjointNode[K comparable, V, W any](
+	n1 node[K, V],
+	n2 node[K, W],
+	shift uint,
+	hashFunc func(key K) uint64,
+) bool {
+	// short-circuit for identical pointers, which hold at least one entry
+	if any(n1) == any(n2) {
+		return false
+	}
+
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedNode[K, W]); ok {
+			return disjointBitmapIndexedNodes(n1, n2, shift, hashFunc)
+		}
+	}
+
+	// Collision nodes, and nodes of different kinds which never meet in a canonical trie.
+	for k := range keysNode(n1) {
+		if _, ok := nodeGet(n2, k, hashFunc(k), shift); ok {
+			return false
+		}
+	}
+	return true
+}
+
+func disjointBitmapIndexedNodes[K comparable, V, W any](
+	n1 *bitmapIndexedNode[K, V],
+	n2 *bitmapIndexedNode[K, W],
+	shift uint,
+	hashFunc func(key K) uint64,
+) bool {
+	for bits := (n1.datamap | n1.nodemap) & (n2.datamap | n2.nodemap); bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		switch {
+		case n1.datamap&bit != 0 && n2.datamap&bit != 0:
+			if n1.keys[popcount(n1.datamap&(bit-1))] == n2.keys[popcount(n2.datamap&(bit-1))] {
+				return false
+			}
+		case n1.datamap&bit != 0:
+			k1 := n1.keys[popcount(n1.datamap&(bit-1))]
+			if _, ok := nodeGet(n2.nodes[popcount(n2.nodemap&(bit-1))], k1, hashFunc(k1), shift+bitsPerLevel); ok {
+				return false
+			}
+		case n2.datamap&bit != 0:
+			k2 := n2.keys[popcount(n2.datamap&(bit-1))]
+			if _, ok := nodeGet(n1.nodes[popcount(n1.nodemap&(bit-1))], k2, hashFunc(k2), shift+bitsPerLevel); ok {
+				return false
+			}
+		default:
+			child1 := n1.nodes[popcount(n1.nodemap&(bit-1))]
+			child2 := n2.nodes[popcount(n2.nodemap&(bit-1))]
+			if !disjointNode(child1, child2, shift+bitsPerLevel, hashFunc) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// subsetKeysNode reports whether every key of n1 is present in the set trie n2.
+func subsetKeysNode[K comparable, V any](n1 node[K, V], n2 setNode[K], shift uint, hashFunc func(key K) uint64) bool {
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedSetNode[K]); ok {
+			return subsetKeysBitmapIndexedNodes(n1, n2, shift, hashFunc)
+		}
+	}
+
+	// Collision nodes, and nodes of different kinds which never meet in a canonical trie.
+	for k := range keysNode(n1) {
+		if !n2.contains(k, hashFunc(k), shift) {
+			return false
+		}
+	}
+	return true
+}
+
+func subsetKeysBitmapIndexedNodes[K comparable, V any](
+	n1 *bitmapIndexedNode[K, V],
+	n2 *bitmapIndexedSetNode[K],
+	shift uint,
+	hashFunc func(key K) uint64,
+) bool {
+	// A sub-node holds at least two keys, so it cannot be contained in a single key.
+	if (n1.datamap|n1.nodemap)&^(n2.datamap|n2.nodemap) != 0 || n1.nodemap&n2.datamap != 0 {
+		return false
+	}
+
+	for bits := n1.datamap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+		k1 := n1.keys[popcount(n1.datamap&(bit-1))]
+
+		if n2.datamap&bit != 0 {
+			if n2.keys[popcount(n2.datamap&(bit-1))] != k1 {
+				return false
+			}
+			continue
+		}
+
+		if !n2.nodes[popcount(n2.nodemap&(bit-1))].contains(k1, hashFunc(k1), shift+bitsPerLevel) {
+			return false
+		}
+	}
+
+	for bits := n1.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+		child1 := n1.nodes[popcount(n1.nodemap&(bit-1))]
+		child2 := n2.nodes[popcount(n2.nodemap&(bit-1))]
+		if !subsetKeysNode(child1, child2, shift+bitsPerLevel, hashFunc) {
+			return false
+		}
+	}
+	return true
+}
+
+// disjointKeysNode reports whether n1 has no keys in the set trie n2.
+func disjointKeysNode[K comparable, V any](n1 node[K, V], n2 setNode[K], shift uint, hashFunc func(key K) uint64) bool {
+	if n1, ok := n1.(*bitmapIndexedNode[K, V]); ok {
+		if n2, ok := n2.(*bitmapIndexedSetNode[K]); ok {
+			return disjointKeysBitmapIndexedNodes(n1, n2, shift, hashFunc)
+		}
+	}
+
+	// Collision nodes, and nodes of different kinds which never meet in a canonical trie.
+	for k := range keysNode(n1) {
+		if n2.contains(k, hashFunc(k), shift) {
+			return false
+		}
+	}
+	return true
+}
+
+func disjointKeysBitmapIndexedNodes[K comparable, V any](
+	n1 *bitmapIndexedNode[K, V],
+	n2 *bitmapIndexedSetNode[K],
+	shift uint,
+	hashFunc func(key K) uint64,
+) bool {
+	for bits := (n1.datamap | n1.nodemap) & (n2.datamap | n2.nodemap); bits != 0; bits &= bits - 1 {
+		bit := bits & -bits
+
+		switch {
+		case n1.datamap&bit != 0 && n2.datamap&bit != 0:
+			if n1.keys[popcount(n1.datamap&(bit-1))] == n2.keys[popcount(n2.datamap&(bit-1))] {
+				return false
+			}
+		case n1.datamap&bit != 0:
+			k1 := n1.keys[popcount(n1.datamap&(bit-1))]
+			if n2.nodes[popcount(n2.nodemap&(bit-1))].contains(k1, hashFunc(k1), shift+bitsPerLevel) {
+				return false
+			}
+		case n2.datamap&bit != 0:
+			k2 := n2.keys[popcount(n2.datamap&(bit-1))]
+			if _, ok := nodeGet(n1.nodes[popcount(n1.nodemap&(bit-1))], k2, hashFunc(k2), shift+bitsPerLevel); ok {
+				return false
+			}
+		default:
+			child1 := n1.nodes[popcount(n1.nodemap&(bit-1))]
+			child2 := n2.nodes[popcount(n2.nodemap&(bit-1))]
+			if !disjointKeysNode(child1, child2, shift+bitsPerLevel, hashFunc) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,213 @@
+package champ
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSubmap(t *testing.T) {
+	// low entropy hash function producing deep tries and collision nodes
+	lowEntropy := func(key string) uint64 { return uint64(len(key)+int(key[len(key)-1])%3) << 58 }
+
+	type entries map[string]int
+	rangeEntries := func(start, end int) entries {
+		e := entries{}
+		for i := start; i < end; i++ {
+			e[fmt.Sprintf("k%d", i)] = i
+		}
+		return e
+	}
+	with := func(e entries, key string, value int) entries {
+		e[key] = value
+		return e
+	}
+
+	for _, tt := range []struct {
+		name       string
+		e1, e2     entries
+		isSubmap   bool
+		keysSubset bool
+		disjoint   bool
+	}{
+		{
+			name:       "empty",
+			e1:         entries{},
+			e2:         entries{},
+			isSubmap:   true,
+			keysSubset: true,
+			disjoint:   true,
+		},
+		{
+			name:       "empty and non-empty",
+			e1:         entries{},
+			e2:         rangeEntries(0, 10),
+			isSubmap:   true,
+			keysSubset: true,
+			disjoint:   true,
+		},
+		{
+			name:       "equal",
+			e1:         rangeEntries(0, 300),
+			e2:         rangeEntries(0, 300),
+			isSubmap:   true,
+			keysSubset: true,
+		},
+		{
+			name:       "proper submap",
+			e1:         rangeEntries(100, 200),
+			e2:         rangeEntries(0, 300),
+			isSubmap:   true,
+			keysSubset: true,
+		},
+		{
+			name: "supermap",
+			e1:   rangeEntries(0, 300),
+			e2:   rangeEntries(100, 200),
+		},
+		{
+			name:       "different value",
+			e1:         with(rangeEntries(100, 200), "k150", -1),
+			e2:         rangeEntries(0, 300),
+			keysSubset: true,
+		},
+		{
+			name: "missing key",
+			e1:   with(rangeEntries(100, 200), "missing", 0),
+			e2:   rangeEntries(0, 300),
+		},
+		{
+			name: "overlapping",
+			e1:   rangeEntries(0, 200),
+			e2:   rangeEntries(100, 300),
+		},
+		{
+			name:     "disjoint",
+			e1:       rangeEntries(0, 150),
+			e2:       rangeEntries(150, 300),
+			disjoint: true,
+		},
+		{
+			name: "single common key",
+			e1:   rangeEntries(0, 150),
+			e2:   with(rangeEntries(150, 300), "k42", 0),
+		},
+	} {
+		for _, hashers := range []struct {
+			name string
+			new  func() (*Map[string, int], *Map[string, int])
+		}{
+			{"default hash function", func() (*Map[string, int], *Map[string, int]) {
+				return New[string, int](), New[string, int]()
+			}},
+			{"shared hash function", func() (*Map[string, int], *Map[string, int]) {
+				m := NewWithHasher[string, int](lowEntropy)
+				return m, m
+			}},
+			{"different hash functions", func() (*Map[string, int], *Map[string, int]) {
+				return NewWithHasher[string, int](lowEntropy), New[string, int]()
+			}},
+		} {
+			t.Run(tt.name+"/"+hashers.name, func(t *testing.T) {
+				m1, m2 := hashers.new()
+				keys := &Set[string]{hasher: m2.hasher}
+				for k, v := range tt.e1 {
+					m1 = m1.Set(k, v)
+				}
+				for k, v := range tt.e2 {
+					m2 = m2.Set(k, v)
+					keys = keys.Add(k)
+				}
+
+				if actual := IsSubmap(m1, m2); actual != tt.isSubmap {
+					t.Errorf("IsSubmap() expected %v, actual %v", tt.isSubmap, actual)
+				}
+				eq := func(v1, v2 int) bool { return v1 == v2 }
+				if actual := IsSubmapFunc(m1, m2, eq); actual != tt.isSubmap {
+					t.Errorf("IsSubmapFunc() expected %v, actual %v", tt.isSubmap, actual)
+				}
+				if actual := KeysSubset(m1, m2); actual != tt.keysSubset {
+					t.Errorf("KeysSubset() expected %v, actual %v", tt.keysSubset, actual)
+				}
+				if actual := Disjoint(m1, m2); actual != tt.disjoint {
+					t.Errorf("Disjoint() expected %v, actual %v", tt.disjoint, actual)
+				}
+				if actual := Disjoint(m2, m1); actual != tt.disjoint {
+					t.Errorf("Disjoint() with swapped arguments expected %v, actual %v", tt.disjoint, actual)
+				}
+				if actual := SubsetKeys(m1, keys); actual != tt.keysSubset {
+					t.Errorf("SubsetKeys() expected %v, actual %v", tt.keysSubset, actual)
+				}
+				if actual := DisjointKeys(m1, keys); actual != tt.disjoint {
+					t.Errorf("DisjointKeys() expected %v, actual %v", tt.disjoint, actual)
+				}
+			})
+		}
+	}
+
+	t.Run("shared subtrees are skipped", func(t *testing.T) {
+		m1 := New[string, int]()
+		for i := range 1000 {
+			m1 = m1.Set(fmt.Sprintf("k%d", i), i)
+		}
+		m2 := m1.Set("extra", 0)
+
+		calls := 0
+		eq := func(v1, v2 int) bool {
+			calls++
+			return v1 == v2
+		}
+		if !IsSubmapFunc(m1, m2, eq) {
+			t.Error("IsSubmapFunc() = false for a submap")
+		}
+		if calls > branchFactor*maxDepth {
+			t.Errorf("IsSubmapFunc() called eq %d times for maps differing in one entry", calls)
+		}
+	})
+
+	t.Run("keys subset with different value types", func(t *testing.T) {
+		m := New[string, int]().Set("a", 1).Set("b", 2)
+		keys := New[string, bool]().Set("a", true).Set("b", false).Set("c", true)
+		if !KeysSubset(m, keys) {
+			t.Error("KeysSubset() = false for a subset of keys")
+		}
+		if Disjoint(m, keys) {
+			t.Error("Disjoint() = true for maps with common keys")
+		}
+	})
+
+	t.Run("keys subset of a set", func(t *testing.T) {
+		m := New[string, int]().Set("a", 1).Set("b", 2)
+		keys := NewSet("a", "b", "c")
+		if !SubsetKeys(m, keys) {
+			t.Error("SubsetKeys() = false for a subset of keys")
+		}
+		if SubsetKeys(m.Set("d", 4), keys) {
+			t.Error("SubsetKeys() = true for a key missing from the set")
+		}
+		if DisjointKeys(m, keys) {
+			t.Error("DisjointKeys() = true for a set with common keys")
+		}
+		if !DisjointKeys(m, NewSet("c", "d")) {
+			t.Error("DisjointKeys() = false for a set without common keys")
+		}
+	})
+}
+
+func TestSetPredicates(t *testing.T) {
+	s1 := NewSet(1, 2, 3)
+	s2 := NewSet(1, 2, 3, 4)
+	s3 := NewSet(5, 6)
+
+	if !s1.IsSubset(s2) {
+		t.Error("IsSubset() = false for a subset")
+	}
+	if s2.IsSubset(s1) {
+		t.Error("IsSubset() = true for a superset")
+	}
+	if !s1.IsDisjoint(s3) {
+		t.Error("IsDisjoint() = false for disjoint sets")
+	}
+	if s1.IsDisjoint(s2) {
+		t.Error("IsDisjoint() = true for overlapping sets")
+	}
+}
@@ -0,0 +1,145 @@
+package champ
+
+// UpdateAction tells UpdateFunc what to do with the entry of a key.
+type UpdateAction int
+
+const (
+	UpdateKeep   UpdateAction = iota // leave the entry as is, ignoring the returned value
+	UpdateSet                        // set the entry to the returned value
+	UpdateDelete                     // delete the entry if present
+)
+
+// updateFunc receives the current value of a key and whether it is present,
+// and returns the new value and what to do with the entry.
+type updateFunc[V any] func(old V, ok bool) (V, UpdateAction)
+
+// Update sets, replaces or deletes the entry of key in a single descent through the trie.
+//
+// fn receives the current value of key and whether it is present,
+// and returns the new value and whether the key should be kept.
+// If fn does not keep the key, the entry is removed or, if absent, not added.
+//
+// A kept key is always set to the returned value, so a new map is returned even if the value is unchanged.
+// m itself is only returned if the key is neither present nor kept.
+// Use UpdateFunc to leave the entry as is and get m itself.
+func (m *Map[K, V]) Update(key K, fn func(old V, ok bool) (V, bool)) *Map[K, V] {
+	return m.update(key, func(old V, ok bool) (V, UpdateAction) {
+		value, keep := fn(old, ok)
+		if keep {
+			return value, UpdateSet
+		}
+		return value, UpdateDelete
+	})
+}
+
+// UpdateFunc is like Update but fn returns what to do with the entry.
+// m itself is returned if fn returns UpdateKeep, or UpdateDelete for an absent key.
+func (m *Map[K, V]) UpdateFunc(key K, fn func(old V, ok bool) (V, UpdateAction)) *Map[K, V] {
+	return m.update(key, fn)
+}
+
+// GetOrSet returns the current value of key and true if present, along with m itself.
+// Otherwise, it returns a map with key set to value, value and false.
+func (m *Map[K, V]) GetOrSet(key K, value V) (*Map[K, V], V, bool) {
+	actual, loaded := value, false
+	result := m.update(key, func(old V, ok bool) (V, UpdateAction) {
+		if ok {
+			actual, loaded = old, true
+			return old, UpdateKeep
+		}
+		return value, UpdateSet
+	})
+	return result, actual, loaded
+}
+
+// SetIfAbsent returns a map with key set to value if key is not present.
+// If key is present, m itself is returned.
+func (m *Map[K, V]) SetIfAbsent(key K, value V) *Map[K, V] {
+	result, _, _ := m.GetOrSet(key, value)
+	return result
+}
+
+// Adjust returns a map with the value of key replaced by fn applied to it.
+// If key is not present, m itself is returned without calling fn.
+// Otherwise, a new map is returned even if fn returns the same value.
+func (m *Map[K, V]) Adjust(key K, fn func(old V) V) *Map[K, V] {
+	return m.update(key, func(old V, ok bool) (V, UpdateAction) {
+		if !ok {
+			return old, UpdateKeep
+		}
+		return fn(old), UpdateSet
+	})
+}
+
+// update applies fn to the entry of key, hashing the key and descending the trie once.
+func (m *Map[K, V]) update(key K, fn updateFunc[V]) *Map[K, V] {
+	h := m.hasher.hash(key)
+
+	if m.root == nil {
+		var zero V
+		value, action := fn(zero, false)
+		if action != UpdateSet {
+			return m
+		}
+		return m.withRoot(&bitmapIndexedNode[K, V]{
+			datamap: uint32(1 << (h & bitMask)),
+			keys:    []K{key},
+			values:  []V{value},
+		}, 1)
+	}
+
+	root, delta := nodeUpdate(m.root, key, h, 0, fn, m.hasher.hashFunc())
+	return m.withRoot(root, m.size+delta)
+}
+
+// Swap returns a map with key set to value, along with the previous value of key and whether it was present.
+// A new map is returned even if the previous value equals value.
+func (m *Map[K, V]) Swap(key K, value V) (*Map[K, V], V, bool) {
+	var previous V
+	loaded := false
+	result := m.update(key, func(old V, ok bool) (V, UpdateAction) {
+		previous, loaded = old, ok
+		return value, UpdateSet
+	})
+	return result, previous, loaded
+}
+
+// Pop returns a map without key, along with the removed value and whether it was present.
+// If key is not present, m itself is returned.
+func (m *Map[K, V]) Pop(key K) (*Map[K, V], V, bool) {
+	var removed V
+	loaded := false
+	result := m.update(key, func(old V, ok bool) (V, UpdateAction) {
+		removed, loaded = old, ok
+		return old, UpdateDelete
+	})
+	return result, removed, loaded
+}
+
+// SetIfValue returns a map with key set to value if its current value equals old,
+// and whether the value was set. Otherwise, m itself is returned.
+func SetIfValue[K, V comparable](m *Map[K, V], key K, old, value V) (*Map[K, V], bool) {
+	swapped := false
+	result := m.update(key, func(current V, ok bool) (V, UpdateAction) {
+		if !ok || current != old {
+			return current, UpdateKeep
+		}
+		swapped = true
+		return value, UpdateSet
+	})
+	return result, swapped
+}
+
+// DeleteIfValue returns a map without key if its current value equals value,
+// and whether the key was deleted. Otherwise, m itself is returned.
+func DeleteIfValue[K, V comparable](m *Map[K, V], key K, value V) (*Map[K, V], bool) {
+	deleted := false
+	result := m.update(key, func(current V, ok bool) (V, UpdateAction) {
+		if !ok || current != value {
+			return current, UpdateKeep
+		}
+		deleted = true
+		return current, UpdateDelete
+	})
+	return result, deleted
+}
@@ -0,0 +1,305 @@
+package champ
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+)
+
+func TestUpdate(t *testing.T) {
+	// keys sharing hash prefixes and colliding completely, to exercise sub-nodes and collision nodes
+	keys := []string{"1", "01", "001", "0000100001", "0001000001", "10", "11"}
+
+	for _, tt := range []struct {
+		name      string
+		fn        func(old int, ok bool) (int, bool)
+		unchanged func(ok bool) bool
+	}{
+		{
+			name:      "increment or insert",
+			fn:        func(old int, ok bool) (int, bool) { return old + 1, true },
+			unchanged: func(bool) bool { return false },
+		},
+		{
+			name:      "delete",
+			fn:        func(old int, ok bool) (int, bool) { return 0, false },
+			unchanged: func(ok bool) bool { return !ok },
+		},
+		{
+			name:      "delete if present, insert otherwise",
+			fn:        func(old int, ok bool) (int, bool) { return 100, !ok },
+			unchanged: func(bool) bool { return false },
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// apply the update to every subset of the keys
+			for mask := range 1 << len(keys) {
+				m := NewWithHasher[string, int](testHashFunc)
+				ref := map[string]int{}
+				for i, k := range keys {
+					if mask&(1<<i) != 0 {
+						m = m.Set(k, i)
+						ref[k] = i
+					}
+				}
+
+				for _, key := range keys {
+					old, ok := ref[key]
+					actual := m.Update(key, tt.fn)
+
+					expectedRef := maps.Clone(ref)
+					if v, keep := tt.fn(old, ok); keep {
+						expectedRef[key] = v
+					} else {
+						delete(expectedRef, key)
+					}
+					expected := NewWithHasher[string, int](testHashFunc)
+					for k, v := range expectedRef {
+						expected = expected.Set(k, v)
+					}
+
+					if actual.Len() != expected.Len() {
+						t.Errorf("mask %b: Update(%q) Len() expected %d, actual %d", mask, key, expected.Len(), actual.Len())
+					}
+					// structural comparison also verifies the canonical form
+					if !equalNode(actual.root, expected.root) {
+						t.Errorf("mask %b: Update(%q) result does not match expected\nactual:\n%s\nexpected:\n%s", mask, key, actual.root, expected.root)
+					}
+					if tt.unchanged(ok) && actual != m {
+						t.Errorf("mask %b: Update(%q) without changes returned a new map", mask, key)
+					}
+				}
+			}
+		})
+	}
+
+	t.Run("single hash computation", func(t *testing.T) {
+		calls := 0
+		m := NewWithHasher[string, int](func(key string) uint64 {
+			calls++
+			return hashKey(key)
+		})
+		for i := range 1000 {
+			m = m.Set(fmt.Sprintf("key%d", i), i)
+		}
+
+		calls = 0
+		m = m.Update("key1", func(old int, ok bool) (int, bool) { return old + 1, true })
+		if calls != 1 {
+			t.Errorf("Update() hashed the key %d times, expected %d", calls, 1)
+		}
+		if v, _ := m.Get("key1"); v != 2 {
+			t.Errorf("Get(%q) expected %d, actual %d", "key1", 2, v)
+		}
+	})
+}
+
+func TestUpdateFunc(t *testing.T) {
+	m := NewWithHasher[string, int](testHashFunc).Set("1", 1).Set("01", 2).Set("10", 3)
+
+	for _, tt := range []struct {
+		name     string
+		key      string
+		fn       func(old int, ok bool) (int, UpdateAction)
+		expected *Map[string, int] // nil if m itself is expected
+	}{
+		{
+			name: "keep a present key",
+			key:  "1",
+			fn:   func(old int, ok bool) (int, UpdateAction) { return old, UpdateKeep },
+		},
+		{
+			name: "keep an absent key",
+			key:  "11",
+			fn:   func(old int, ok bool) (int, UpdateAction) { return 100, UpdateKeep },
+		},
+		{
+			name: "delete an absent key",
+			key:  "11",
+			fn:   func(old int, ok bool) (int, UpdateAction) { return old, UpdateDelete },
+		},
+		{
+			name:     "set a colliding key",
+			key:      "01",
+			fn:       func(old int, ok bool) (int, UpdateAction) { return old + 1, UpdateSet },
+			expected: m.Set("01", 3),
+		},
+		{
+			name:     "delete a colliding key",
+			key:      "01",
+			fn:       func(old int, ok bool) (int, UpdateAction) { return old, UpdateDelete },
+			expected: m.Delete("01"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := m.UpdateFunc(tt.key, tt.fn)
+			if tt.expected == nil {
+				if actual != m {
+					t.Errorf("UpdateFunc(%q) expected m itself, actual a new map", tt.key)
+				}
+				return
+			}
+			if !Equal(actual, tt.expected) {
+				t.Errorf("UpdateFunc(%q) result does not match expected", tt.key)
+			}
+		})
+	}
+
+	t.Run("Update keeping the same value", func(t *testing.T) {
+		// Update always sets a kept key, so only the entries are preserved
+		actual := m.Update("1", func(old int, ok bool) (int, bool) { return old, true })
+		if !Equal(actual, m) {
+			t.Error("Update() keeping the same value changed the entries")
+		}
+	})
+}
+
+func TestGetOrSet(t *testing.T) {
+	m := New[string, int]().Set("a", 1)
+
+	m1, v, loaded := m.GetOrSet("a", 2)
+	if m1 != m || v != 1 || !loaded {
+		t.Errorf("GetOrSet(%q) expected (m, 1, true), actual (%p, %d, %v)", "a", m1, v, loaded)
+	}
+
+	m2, v, loaded := m.GetOrSet("b", 2)
+	if v != 2 || loaded {
+		t.Errorf("GetOrSet(%q) expected (2, false), actual (%d, %v)", "b", v, loaded)
+	}
+	if actual, _ := m2.Get("b"); m2.Len() != 2 || actual != 2 {
+		t.Errorf("GetOrSet(%q) did not set the value", "b")
+	}
+
+	if m3 := m2.SetIfAbsent("b", 3); m3 != m2 {
+		t.Error("SetIfAbsent() of an existing key returned a new map")
+	}
+	if m3 := m2.SetIfAbsent("c", 3); m3.Len() != 3 {
+		t.Errorf("SetIfAbsent() Len() expected %d, actual %d", 3, m3.Len())
+	}
+}
+
+func TestAdjust(t *testing.T) {
+	m := New[string, int]().Set("a", 1)
+
+	called := false
+	if actual := m.Adjust("missing", func(v int) int { called = true; return v }); actual != m {
+		t.Error("Adjust() of a missing key returned a new map")
+	}
+	if called {
+		t.Error("Adjust() of a missing key called fn")
+	}
+
+	actual := m.Adjust("a", func(v int) int { return v * 10 })
+	if v, _ := actual.Get("a"); v != 10 {
+		t.Errorf("Get(%q) expected %d, actual %d", "a", 10, v)
+	}
+	if v, _ := m.Get("a"); v != 1 {
+		t.Errorf("Adjust() modified the original map, Get(%q) = %d", "a", v)
+	}
+}
+
+func TestSwap(t *testing.T) {
+	m := New[string, int]().Set("a", 1)
+
+	m1, old, ok := m.Swap("a", 2)
+	if old != 1 || !ok {
+		t.Errorf("Swap(%q) expected (1, true), actual (%d, %v)", "a", old, ok)
+	}
+	if v, _ := m1.Get("a"); v != 2 || m1.Len() != 1 {
+		t.Errorf("Swap(%q) did not replace the value", "a")
+	}
+
+	m2, old, ok := m1.Swap("b", 3)
+	if old != 0 || ok {
+		t.Errorf("Swap(%q) expected (0, false), actual (%d, %v)", "b", old, ok)
+	}
+	if v, _ := m2.Get("b"); v != 3 || m2.Len() != 2 {
+		t.Errorf("Swap(%q) did not add the value", "b")
+	}
+}
+
+func TestPop(t *testing.T) {
+	m := New[string, int]()
+	for i := range 100 {
+		m = m.Set(fmt.Sprintf("key%d", i), i)
+	}
+
+	for i := range 100 {
+		key := fmt.Sprintf("key%d", i)
+		var (
+			v  int
+			ok bool
+		)
+		m, v, ok = m.Pop(key)
+		if v != i || !ok {
+			t.Errorf("Pop(%q) expected (%d, true), actual (%d, %v)", key, i, v, ok)
+		}
+		if m.Len() != 99-i {
+			t.Errorf("Pop(%q) Len() expected %d, actual %d", key, 99-i, m.Len())
+		}
+	}
+	if m.root != nil {
+		t.Error("Pop() of the last key left a root node")
+	}
+
+	if actual, v, ok := m.Pop("missing"); actual != m || v != 0 || ok {
+		t.Errorf("Pop(%q) expected (m, 0, false), actual (%p, %d, %v)", "missing", actual, v, ok)
+	}
+}
+
+func TestCompareAndSet(t *testing.T) {
+	m := New[string, int]().Set("a", 1)
+
+	for _, tt := range []struct {
+		name     string
+		apply    func() (*Map[string, int], bool)
+		expected map[string]int
+		ok       bool
+	}{
+		{
+			name:     "set matching value",
+			apply:    func() (*Map[string, int], bool) { return SetIfValue(m, "a", 1, 2) },
+			expected: map[string]int{"a": 2},
+			ok:       true,
+		},
+		{
+			name:     "set mismatching value",
+			apply:    func() (*Map[string, int], bool) { return SetIfValue(m, "a", 0, 2) },
+			expected: map[string]int{"a": 1},
+		},
+		{
+			name:     "set missing key",
+			apply:    func() (*Map[string, int], bool) { return SetIfValue(m, "b", 0, 2) },
+			expected: map[string]int{"a": 1},
+		},
+		{
+			name:     "delete matching value",
+			apply:    func() (*Map[string, int], bool) { return DeleteIfValue(m, "a", 1) },
+			expected: map[string]int{},
+			ok:       true,
+		},
+		{
+			name:     "delete mismatching value",
+			apply:    func() (*Map[string, int], bool) { return DeleteIfValue(m, "a", 2) },
+			expected: map[string]int{"a": 1},
+		},
+		{
+			name:     "delete missing key",
+			apply:    func() (*Map[string, int], bool) { return DeleteIfValue(m, "b", 0) },
+			expected: map[string]int{"a": 1},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := tt.apply()
+			if ok != tt.ok {
+				t.Errorf("expected ok=%v, actual %v", tt.ok, ok)
+			}
+			if !ok && actual != m {
+				t.Error("unsuccessful operation returned a new map")
+			}
+			if !maps.Equal(maps.Collect(actual.All()), tt.expected) {
+				t.Errorf("expected %v, actual %v", tt.expected, maps.Collect(actual.All()))
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package champ
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Validate checks that the trie of the map satisfies every invariant of the canonical form,
+// and returns an error describing the first violation found.
+//
+// The checks are:
+//   - bitmaps of a node are disjoint, and have as many bits as the node has entries and children
+//   - every key is stored at the position given by its hash
+//   - sub-nodes hold at least two entries, as a single entry is stored in the parent
+//   - collision nodes are only at the maximum depth and hold at least two distinct keys with equal hashes
+//   - the size of the map matches the number of entries
+//
+// A map built through the API always satisfies them,
+// so Validate is meant for tests and for maps decoded from untrusted data.
+func (m *Map[K, V]) Validate() error {
+	if m.root == nil {
+		if m.size != 0 {
+			return fmt.Errorf("champ: size %d of an empty trie", m.size)
+		}
+		return nil
+	}
+
+	v := validator[K, V]{hashFunc: m.hasher.hashFunc()}
+	count, err := v.node(m.root, 0, 0)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("champ: empty root node")
+	}
+	if count != m.size {
+		return fmt.Errorf("champ: size %d of a trie with %d entries", m.size, count)
+	}
+	return nil
+}
+
+// validator walks a trie for Validate, tracking the path to the current node.
+type validator[K comparable, V any] struct {
+	hashFunc func(key K) uint64
+	path     []int // positions of the nodes from the root
+}
+
+// node validates the subtree n at shift, whose hash path consumed so far is prefix,
+// and returns the number of its entries.
+func (v *validator[K, V]) node(n node[K, V], prefix uint64, shift uint) (int, error) {
+	switch n := n.(type) {
+	case *bitmapIndexedNode[K, V]:
+		return v.bitmapIndexedNode(n, prefix, shift)
+	case *collisionNode[K, V]:
+		return v.collisionNode(n, prefix, shift)
+	default:
+		return 0, v.errorf("unknown node type %T", n)
+	}
+}
+
+func (v *validator[K, V]) bitmapIndexedNode(n *bitmapIndexedNode[K, V], prefix uint64, shift uint) (int, error) {
+	switch {
+	case shift >= maxDepth*bitsPerLevel:
+		return 0, v.errorf("bitmap indexed node below the maximum depth")
+	case n.datamap&n.nodemap != 0:
+		return 0, v.errorf("datamap %032b and nodemap %032b overlap", n.datamap, n.nodemap)
+	case len(n.keys) != popcount(n.datamap) || len(n.values) != len(n.keys):
+		return 0, v.errorf("datamap %032b with %d keys and %d values", n.datamap, len(n.keys), len(n.values))
+	case len(n.nodes) != popcount(n.nodemap):
+		return 0, v.errorf("nodemap %032b with %d children", n.nodemap, len(n.nodes))
+	}
+
+	i := 0
+	for bits := n.datamap; bits != 0; bits &= bits - 1 {
+		bit := bitIndex(bits & -bits)
+		if !hashHasPrefix(v.hashFunc(n.keys[i]), prefix|uint64(bit)<<shift, shift+bitsPerLevel) {
+			return 0, v.errorf("key %v at position %d does not match its hash", n.keys[i], bit)
+		}
+		i++
+	}
+
+	count := len(n.keys)
+	i = 0
+	for bits := n.nodemap; bits != 0; bits &= bits - 1 {
+		bit := bitIndex(bits & -bits)
+		v.path = append(v.path, bit)
+		c, err := v.node(n.nodes[i], prefix|uint64(bit)<<shift, shift+bitsPerLevel)
+		if err != nil {
+			return 0, err
+		}
+		if c < 2 {
+			return 0, v.errorf("sub-node with fewer than two entries")
+		}
+		v.path = v.path[:len(v.path)-1]
+		count += c
+		i++
+	}
+	return count, nil
+}
+
+func (v *validator[K, V]) collisionNode(n *collisionNode[K, V], prefix uint64, shift uint) (int, error) {
+	switch {
+	case shift != maxDepth*bitsPerLevel:
+		return 0, v.errorf("collision node above the maximum depth")
+	case len(n.keys) < 2:
+		return 0, v.errorf("collision node with fewer than two keys")
+	case len(n.values) != len(n.keys):
+		return 0, v.errorf("collision node with %d keys and %d values", len(n.keys), len(n.values))
+	}
+
+	var seen map[K]struct{}
+	if len(n.keys) > collisionIndexThreshold {
+		seen = make(map[K]struct{}, len(n.keys))
+	}
+	for i, k := range n.keys {
+		if !hashHasPrefix(v.hashFunc(k), prefix, shift) {
+			return 0, v.errorf("key %v does not match its hash", k)
+		}
+
+		duplicate := false
+		if seen != nil {
+			_, duplicate = seen[k]
+			seen[k] = struct{}{}
+		} else {
+			duplicate = slices.Contains(n.keys[:i], k)
+		}
+		if duplicate {
+			return 0, v.errorf("duplicate key %v", k)
+		}
+	}
+	return len(n.keys), nil
+}
+
+// hashHasPrefix reports whether the lowest bits bits of hash are equal to prefix.
+func hashHasPrefix(hash, prefix uint64, bits uint) bool {
+	if bits >= 64 {
+		return hash == prefix
+	}
+	return hash&(1<<bits-1) == prefix
+}
+
+// errorf returns an error prefixed by the path to the current node.
+func (v *validator[K, V]) errorf(format string, args ...any) error {
+	var path strings.Builder
+	path.WriteString("root")
+	for _, bit := range v.path {
+		fmt.Fprintf(&path, "/%d", bit)
+	}
+	return fmt.Errorf("champ: node %s: %s", path.String(), fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,169 @@
+package champ
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	// low entropy hash function producing deep tries and collision nodes
+	lowEntropy := func(key string) uint64 { return uint64(len(key)+int(key[len(key)-1])%3) << 58 }
+
+	t.Run("valid", func(t *testing.T) {
+		large := New[string, int]()
+		for i := range 5000 {
+			large = large.Set(fmt.Sprintf("k%d", i), i)
+		}
+		deep := NewWithHasher[string, int](lowEntropy)
+		for i := range 500 {
+			deep = deep.Set(fmt.Sprintf("k%d", i), i)
+		}
+
+		for _, tt := range []struct {
+			name string
+			m    *Map[string, int]
+		}{
+			{name: "empty", m: New[string, int]()},
+			{name: "large map", m: large},
+			{name: "low entropy hash function", m: deep},
+			{name: "deleted down to a collision", m: NewWithHasher[string, int](testHashFunc).Set("1", 1).Set("01", 2).Set("001", 3).Delete("01")},
+			{name: "FromMap", m: FromMap(map[string]int{"a": 1, "b": 2, "c": 3})},
+			{
+				name: "Builder",
+				m: func() *Map[string, int] {
+					b := deep.Transient()
+					for i := range 250 {
+						b.Delete(fmt.Sprintf("k%d", i))
+					}
+					return b.Persistent()
+				}(),
+			},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				if err := tt.m.Validate(); err != nil {
+					t.Errorf("Validate() returned error: %v", err)
+				}
+			})
+		}
+	})
+
+	// collisionChain returns a root holding the keys in a collision node at the maximum depth.
+	collisionChain := func(keys ...string) node[string, int] {
+		entries := make([]buildEntry[string, int], len(keys))
+		for i, k := range keys {
+			entries[i] = buildEntry[string, int]{hash: 1, key: k, value: i}
+		}
+		return buildCollision(entries, 0)
+	}
+	manyKeys := make([]string, collisionIndexThreshold+1)
+	for i := range manyKeys {
+		manyKeys[i] = strings.Repeat("0", i) + "1"
+	}
+
+	for _, tt := range []struct {
+		name     string
+		root     node[string, int]
+		size     int
+		expected string
+	}{
+		{
+			name:     "size of an empty trie",
+			root:     nil,
+			size:     1,
+			expected: "champ: size 1 of an empty trie",
+		},
+		{
+			name:     "size mismatch",
+			root:     &bitmapIndexedNode[string, int]{datamap: 1 << 1, keys: []string{"1"}, values: []int{1}},
+			size:     2,
+			expected: "champ: size 2 of a trie with 1 entries",
+		},
+		{
+			name:     "empty root node",
+			root:     &bitmapIndexedNode[string, int]{},
+			size:     0,
+			expected: "champ: empty root node",
+		},
+		{
+			name: "overlapping bitmaps",
+			root: &bitmapIndexedNode[string, int]{
+				datamap: 1 << 1,
+				nodemap: 1 << 1,
+				keys:    []string{"1"},
+				values:  []int{1},
+				nodes:   []node[string, int]{collisionChain("1", "01")},
+			},
+			size:     3,
+			expected: "champ: node root: datamap 00000000000000000000000000000010 and nodemap 00000000000000000000000000000010 overlap",
+		},
+		{
+			name:     "missing key",
+			root:     &bitmapIndexedNode[string, int]{datamap: 0b11, keys: []string{"1"}, values: []int{1}},
+			size:     1,
+			expected: "champ: node root: datamap 00000000000000000000000000000011 with 1 keys and 1 values",
+		},
+		{
+			name:     "missing child",
+			root:     &bitmapIndexedNode[string, int]{nodemap: 1},
+			size:     0,
+			expected: "champ: node root: nodemap 00000000000000000000000000000001 with 0 children",
+		},
+		{
+			name:     "key at a wrong position",
+			root:     &bitmapIndexedNode[string, int]{datamap: 1 << 2, keys: []string{"1"}, values: []int{1}},
+			size:     1,
+			expected: "champ: node root: key 1 at position 2 does not match its hash",
+		},
+		{
+			name: "single entry sub-node",
+			root: &bitmapIndexedNode[string, int]{
+				nodemap: 1 << 1,
+				nodes:   []node[string, int]{&bitmapIndexedNode[string, int]{datamap: 1, keys: []string{"1"}, values: []int{1}}},
+			},
+			size:     1,
+			expected: "champ: node root/1: sub-node with fewer than two entries",
+		},
+		{
+			name:     "collision node above the maximum depth",
+			root:     &collisionNode[string, int]{keys: []string{"1", "01"}, values: []int{1, 2}},
+			size:     2,
+			expected: "champ: node root: collision node above the maximum depth",
+		},
+		{
+			name:     "single key collision node",
+			root:     collisionChain("1"),
+			size:     1,
+			expected: "champ: node root/1/0/0/0/0/0/0/0/0/0/0/0/0: collision node with fewer than two keys",
+		},
+		{
+			name:     "collision node with a different hash",
+			root:     collisionChain("1", "11"),
+			size:     2,
+			expected: "champ: node root/1/0/0/0/0/0/0/0/0/0/0/0/0: key 11 does not match its hash",
+		},
+		{
+			name:     "duplicate keys",
+			root:     collisionChain("1", "01", "1"),
+			size:     3,
+			expected: "champ: node root/1/0/0/0/0/0/0/0/0/0/0/0/0: duplicate key 1",
+		},
+		{
+			name:     "duplicate keys in a large collision node",
+			root:     collisionChain(append(manyKeys, "01")...),
+			size:     len(manyKeys) + 1,
+			expected: "champ: node root/1/0/0/0/0/0/0/0/0/0/0/0/0: duplicate key 01",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Map[string, int]{root: tt.root, size: tt.size, hasher: &hasher[string]{fn: testHashFunc}}
+			err := m.Validate()
+			if err == nil {
+				t.Fatalf("Validate() expected error %q, actual nil", tt.expected)
+			}
+			if err.Error() != tt.expected {
+				t.Errorf("Validate() expected error %q, actual %q", tt.expected, err.Error())
+			}
+		})
+	}
+}